@@ -0,0 +1,385 @@
+package rat128
+
+import (
+	"container/heap"
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+// Decimal expansions of some constants, truncated to far more precision than
+// is needed to find their best approximations in N.
+const (
+	piDigits = "3.14159265358979323846264338327950288419716939937510582097494459"
+	eDigits  = "2.71828182845904523536028747135266249775724709369995957496696763"
+)
+
+// ApproxBigRat returns the best rational approximation of r whose denominator
+// is at most maxDen, which is the nearest such fraction to r.
+// ApproxBigRat returns ErrDenInvalid if maxDen is not positive and
+// ErrNumOverflow if the numerator of the approximation does not fit.
+func ApproxBigRat(r *big.Rat, maxDen int64) (N, error) {
+	if maxDen <= 0 {
+		return N{}, ErrDenInvalid
+	}
+	neg := r.Sign() < 0
+	num := new(big.Int).Abs(r.Num())
+	den := new(big.Int).Set(r.Denom())
+	limit := big.NewInt(maxDen)
+	// p0/q0 and p1/q1 are successive convergents of the continued fraction
+	// expansion of |r|, which we compute until the denominator would exceed
+	// the limit or the expansion terminates
+	p0, q0 := big.NewInt(0), big.NewInt(1)
+	p1, q1 := big.NewInt(1), big.NewInt(0)
+	a, r0 := new(big.Int), new(big.Int)
+	for den.Sign() != 0 {
+		a.QuoRem(num, den, r0)
+		q2 := new(big.Int).Mul(a, q1)
+		q2.Add(q2, q0)
+		if q2.Cmp(limit) > 0 {
+			break
+		}
+		p2 := new(big.Int).Mul(a, p1)
+		p2.Add(p2, p0)
+		p0, q0, p1, q1 = p1, q1, p2, q2
+		num, den = den, num.Set(r0)
+	}
+	best := new(big.Rat).SetFrac(p1, q1)
+	if den.Sign() != 0 {
+		// the expansion was cut short, so the best approximation is either
+		// the last convergent or the semiconvergent with the largest
+		// denominator within the limit
+		k := new(big.Int).Sub(limit, q0)
+		k.Quo(k, q1)
+		sp := new(big.Int).Mul(k, p1)
+		sp.Add(sp, p0)
+		sq := new(big.Int).Mul(k, q1)
+		sq.Add(sq, q0)
+		semi := new(big.Rat).SetFrac(sp, sq)
+		abs := new(big.Rat).Abs(r)
+		d1 := new(big.Rat).Sub(abs, semi)
+		d2 := new(big.Rat).Sub(abs, best)
+		if d1.Abs(d1).Cmp(d2.Abs(d2)) < 0 {
+			best = semi
+		}
+	}
+	if neg {
+		best.Neg(best)
+	}
+	return FromBigRat(best)
+}
+
+// ApproxPi returns the best rational approximation of π whose denominator is
+// at most maxDen. Since the numerator must fit in int64, maxDen is limited to
+// math.MaxInt64/4; also, maxDen is raised to 1 if it is less than that.
+func ApproxPi(maxDen int64) N {
+	return approxConst(piDigits, maxDen)
+}
+
+// ApproxE returns the best rational approximation of e whose denominator is
+// at most maxDen, with the same limits on maxDen as ApproxPi.
+func ApproxE(maxDen int64) N {
+	return approxConst(eDigits, maxDen)
+}
+
+// approxConst returns the best approximation of the constant with the given
+// decimal digits, with maxDen clamped to [1, math.MaxInt64/4].
+func approxConst(digits string, maxDen int64) N {
+	r, ok := new(big.Rat).SetString(digits)
+	if !ok {
+		panic("rat128: invalid constant " + digits)
+	}
+	maxDen = min(max(maxDen, 1), math.MaxInt64/4)
+	x, err := ApproxBigRat(r, maxDen)
+	if err != nil {
+		panic(err)
+	}
+	return x
+}
+
+// SimplestBetween returns the simplest rational number strictly between lo
+// and hi, which is the one with the smallest denominator and, among those,
+// the smallest absolute value. The bounds may be given in either order.
+// SimplestBetween returns ErrEmpty if lo == hi, since there is no number
+// strictly between them, and an overflow error if the result does not fit.
+func SimplestBetween(lo, hi N) (N, error) {
+	switch c := lo.Cmp(hi); {
+	case c == 0:
+		return N{}, ErrEmpty
+	case c > 0:
+		lo, hi = hi, lo
+	}
+	switch {
+	case lo.Sign() < 0 && hi.Sign() > 0:
+		return N{}, nil
+	case hi.Sign() <= 0:
+		z, err := SimplestBetween(hi.Neg(), lo.Neg())
+		return z.Neg(), err
+	}
+	m, n, err := simplestBetween(uint64(lo.Num()), uint64(lo.Den()), uint64(hi.Num()), uint64(hi.Den()))
+	if err != nil {
+		return N{}, err
+	}
+	return tryAlreadyReduced(int64(m), int64(n))
+}
+
+// Simplify returns the simplest rational number y with |x-y| <= |tol|, where
+// simplest means having the smallest denominator and, among those, the
+// smallest absolute value. Simplify returns an overflow error if x-|tol| or
+// x+|tol| overflows.
+func (x N) Simplify(tol N) (N, error) {
+	tol = tol.Abs()
+	if tol.IsZero() {
+		return x, nil
+	}
+	lo, err := x.TrySub(tol)
+	if err != nil {
+		return N{}, err
+	}
+	hi, err := x.TryAdd(tol)
+	if err != nil {
+		return N{}, err
+	}
+	// the simplest value in [lo, hi] is either one of the endpoints or the
+	// simplest value in (lo, hi)
+	best := lo
+	if simpler(hi, best) {
+		best = hi
+	}
+	if y, err := SimplestBetween(lo, hi); err == nil && simpler(y, best) {
+		best = y
+	}
+	return best, nil
+}
+
+// ApproximateWithResidual returns the best rational approximation of x whose
+// denominator is at most maxDen, as found by ApproxBigRat, along with the
+// exact signed residual x-approx, so that approx+residual == x. If the
+// denominator of x is already at most maxDen, approx is x and residual is 0.
+// ApproximateWithResidual returns ErrDenInvalid if maxDen is not positive and
+// an overflow error if the residual does not fit, which may happen when the
+// denominators of x and approx are both large.
+func (x N) ApproximateWithResidual(maxDen int64) (approx, residual N, err error) {
+	if maxDen <= 0 {
+		return N{}, N{}, ErrDenInvalid
+	} else if x.Den() <= maxDen {
+		return x, N{}, nil
+	}
+	r := x.BigRat()
+	approx, err = ApproxBigRat(r, maxDen)
+	if err != nil {
+		return N{}, N{}, err
+	}
+	residual, err = x.TrySub(approx)
+	if err != nil {
+		residual, err = FromBigRat(r.Sub(r, approx.BigRat()))
+		if err != nil {
+			return N{}, N{}, err
+		}
+	}
+	return approx, residual, nil
+}
+
+// maxCandidates is the most values that CandidatesWithin returns.
+const maxCandidates = 16
+
+// CandidatesWithin returns the fractions within |tol| of x whose denominators
+// are at most maxDen, in order of increasing denominator and then increasing
+// distance from x, with the lesser value first if two are equally far from x.
+// This can be used to suggest "nice" fractions that x is close to. Only the
+// first 16 such fractions are returned.
+// The fractions are found by repeatedly splitting the interval around x at
+// the value nearest to x among its simplest values, as found with
+// SimplestBetween, so the time taken depends on the number of fractions
+// returned rather than on maxDen.
+// CandidatesWithin returns ErrDenInvalid if maxDen is not positive, and an
+// overflow error if x-|tol| or x+|tol| overflows.
+func (x N) CandidatesWithin(tol N, maxDen int64) ([]N, error) {
+	if maxDen <= 0 {
+		return nil, ErrDenInvalid
+	}
+	tol = tol.Abs()
+	lo, err := x.TrySub(tol)
+	if err != nil {
+		return nil, err
+	}
+	hi, err := x.TryAdd(tol)
+	if err != nil {
+		return nil, err
+	}
+	xr := x.BigRat()
+	var h candidateHeap
+	push := func(lo, hi N, loIn, hiIn bool) {
+		best, ok := x.nearestSimplestIn(lo, hi, loIn, hiIn)
+		if !ok || best.Den() > maxDen {
+			return
+		}
+		d := new(big.Rat).Sub(best.BigRat(), xr)
+		heap.Push(&h, candidateRange{lo, hi, loIn, hiIn, best, d.Abs(d)})
+	}
+	push(lo, hi, true, true)
+	var cs []N
+	for h.Len() > 0 && len(cs) < maxCandidates {
+		// every other value in r has a larger denominator than r.best or is
+		// no closer to x, and the same goes for the ranges still in the heap,
+		// so splitting r at r.best yields the next candidates on either side
+		r := heap.Pop(&h).(candidateRange)
+		cs = append(cs, r.best)
+		push(r.lo, r.best, r.loIn, false)
+		push(r.best, r.hi, false, r.hiIn)
+	}
+	return cs, nil
+}
+
+// candidateRange is an interval from lo to hi, which includes each endpoint
+// only if the corresponding flag is true, along with its best candidate, as
+// found by nearestSimplestIn, and that value's distance from the target of
+// CandidatesWithin.
+type candidateRange struct {
+	lo, hi     N
+	loIn, hiIn bool
+	best       N
+	dist       *big.Rat
+}
+
+// candidateHeap implements heap.Interface, ordering candidateRanges by
+// the denominators of their best candidates, then by distance, and then by
+// the values themselves.
+type candidateHeap []candidateRange
+
+func (h candidateHeap) Len() int { return len(h) }
+
+func (h candidateHeap) Less(i, j int) bool {
+	if h[i].best.n != h[j].best.n {
+		return h[i].best.n < h[j].best.n
+	}
+	if c := h[i].dist.Cmp(h[j].dist); c != 0 {
+		return c < 0
+	}
+	return h[i].best.Lt(h[j].best)
+}
+
+func (h candidateHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *candidateHeap) Push(x any) { *h = append(*h, x.(candidateRange)) }
+
+func (h *candidateHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// nearestSimplestIn returns the value nearest to x from lo to hi, which
+// includes each endpoint only if the corresponding flag is true, among those
+// with the smallest denominator, and true, or else returns 0 and false if
+// the interval is empty or its simplest value does not fit. If two values are
+// equally near to x, the lesser one is returned.
+func (x N) nearestSimplestIn(lo, hi N, loIn, hiIn bool) (N, bool) {
+	s, ok := simplestIn(lo, hi, loIn, hiIn)
+	if !ok {
+		return N{}, false
+	}
+	// every p/q in the interval is in lowest terms, since otherwise it
+	// would have a smaller denominator than s; so find the integers p with
+	// plo <= p <= phi, and pick the one nearest to x*q
+	q := s.Den()
+	plo, exact := mulFloorBig(lo, q)
+	if !exact || !loIn {
+		plo.Add(plo, big.NewInt(1))
+	}
+	phi, exact := mulFloorBig(hi, q)
+	if exact && !hiIn {
+		phi.Sub(phi, big.NewInt(1))
+	}
+	// x*q = p + f for integer p and 0 <= f < 1; round p up if f > 1/2
+	p, _ := mulFloorBig(x, q)
+	f := new(big.Rat).Mul(x.BigRat(), new(big.Rat).SetInt64(q))
+	f.Sub(f, new(big.Rat).SetInt(p))
+	if f.Cmp(big.NewRat(1, 2)) > 0 {
+		p.Add(p, big.NewInt(1))
+	}
+	if p.Cmp(plo) < 0 {
+		p = plo
+	} else if p.Cmp(phi) > 0 {
+		p = phi
+	}
+	if !p.IsInt64() || p.Int64() == math.MinInt64 {
+		// only possible for very large values; s is no farther from zero
+		return s, true
+	}
+	return N{p.Int64(), q - 1}, true
+}
+
+// mulFloorBig returns floor(x*q) and true if x*q is an integer.
+func mulFloorBig(x N, q int64) (*big.Int, bool) {
+	z, r := new(big.Int).Mul(big.NewInt(x.Num()), big.NewInt(q)), new(big.Int)
+	z.DivMod(z, big.NewInt(x.Den()), r)
+	return z, r.Sign() == 0
+}
+
+// simplestIn returns the simplest value from lo to hi, which includes each
+// endpoint only if the corresponding flag is true, and true, or else returns
+// 0 and false if the interval is empty or its simplest value does not fit.
+func simplestIn(lo, hi N, loIn, hiIn bool) (N, bool) {
+	if lo == hi {
+		return lo, loIn && hiIn
+	}
+	best, ok := lo, loIn
+	if hiIn && (!ok || simpler(hi, best)) {
+		best, ok = hi, true
+	}
+	if z, err := SimplestBetween(lo, hi); err == nil && (!ok || simpler(z, best)) {
+		best, ok = z, true
+	}
+	return best, ok
+}
+
+// simpler returns true if x is simpler than y, meaning it has a smaller
+// denominator or, if the denominators are equal, a smaller absolute value.
+func simpler(x, y N) bool {
+	if x.n != y.n {
+		return x.n < y.n
+	}
+	return abs64(x.m) < abs64(y.m)
+}
+
+// simplestBetween returns the simplest fraction m/n strictly between a/b and
+// c/d, where 0 <= a/b < c/d and d == 0 indicates that c/d is infinite.
+//
+// This is the Stern-Brocot descent, but it moves through the tree by whole
+// runs at a time (i.e., by continued fraction terms) instead of one mediant
+// at a time, so it takes at most a logarithmic number of steps.
+func simplestBetween(a, b, c, d uint64) (m, n uint64, err error) {
+	// k is the integer part of a/b
+	k := a / b
+	// if there is an integer in the interval, the simplest is k+1
+	var qc, rc uint64
+	if d != 0 {
+		qc, rc = c/d, c%d
+	}
+	if d == 0 || qc > k+1 || (qc == k+1 && rc > 0) {
+		if k+1 > math.MaxInt64 {
+			return 0, 0, ErrNumOverflow
+		}
+		return k + 1, 1, nil
+	}
+	// otherwise, k <= a/b < c/d <= k+1, so we subtract k from both bounds and
+	// invert them, then recurse; we know that c/d-k is positive and that
+	// c-k*d doesn't overflow because it is at most d
+	m, n, err = simplestBetween(d, (qc-k)*d+rc, b, a%b)
+	if err != nil {
+		// the numerator and denominator swap when inverting
+		if err == ErrNumOverflow {
+			return 0, 0, ErrDenOverflow
+		}
+		return 0, 0, ErrNumOverflow
+	}
+	// the result is k + 1/(m/n) = (k*m + n)/m
+	hi, lo := bits.Mul64(k, m)
+	lo, carry := bits.Add64(lo, n, 0)
+	if hi != 0 || carry != 0 || lo > math.MaxInt64 {
+		return 0, 0, ErrNumOverflow
+	}
+	return lo, m, nil
+}
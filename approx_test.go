@@ -0,0 +1,264 @@
+package rat128_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/kbolino/rat128"
+)
+
+func TestSimplestBetween(t *testing.T) {
+	cases := []struct {
+		Lo, Hi, Z rat128.N
+		Err       error
+	}{
+		{New(1, 2), New(1, 2), Zero, rat128.ErrEmpty},
+		{New(0, 1), New(1, 1), New(1, 2), nil},
+		{New(1, 1), New(0, 1), New(1, 2), nil},
+		{New(-1, 1), New(1, 1), New(0, 1), nil},
+		{New(-1, 2), New(3, 1), New(0, 1), nil},
+		{New(1, 2), New(3, 1), New(1, 1), nil},
+		{New(1, 1), New(3, 1), New(2, 1), nil},
+		{New(-3, 1), New(-1, 1), New(-2, 1), nil},
+		{New(-1, 1), New(0, 1), New(-1, 2), nil},
+		{New(1, 3), New(1, 2), New(2, 5), nil},
+		{New(3, 10), New(1, 3), New(4, 13), nil},
+		{New(314, 100), New(315, 100), New(22, 7), nil},
+		{New(3141592, 1000000), New(3141593, 1000000), New(355, 113), nil},
+		{New(-3141593, 1000000), New(-3141592, 1000000), New(-355, 113), nil},
+		{New(1, 3), New(1, 1), New(1, 2), nil},
+		{New(math.MaxInt64-1, 1), New(math.MaxInt64, 1), Zero, rat128.ErrNumOverflow},
+		{New(0, 1), New(1, math.MaxInt64), Zero, rat128.ErrDenOverflow},
+		{New(math.MaxInt64-2, 1), New(math.MaxInt64, 1), New(math.MaxInt64-1, 1), nil},
+		{New(1, math.MaxInt64-1), New(1, math.MaxInt64-2), Zero, rat128.ErrDenOverflow},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s,%s)", c.Lo.RationalString("_"), c.Hi.RationalString("_")), func(t *testing.T) {
+			z, err := rat128.SimplestBetween(c.Lo, c.Hi)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if c.Err == nil && z != c.Z {
+				t.Errorf("got %v, want %v", z, c.Z)
+			}
+		})
+	}
+}
+
+func TestApproxBigRat(t *testing.T) {
+	cases := []struct {
+		R      string
+		MaxDen int64
+		Z      rat128.N
+		Err    error
+	}{
+		{"0", 10, New(0, 1), nil},
+		{"1/3", 10, New(1, 3), nil},
+		{"1/3", 2, New(1, 2), nil},
+		{"-1/3", 2, New(-1, 2), nil},
+		{"1/3", 1, New(0, 1), nil},
+		{"3.14159", 1000, New(355, 113), nil},
+		{"0.1", 5, New(0, 1), nil},
+		{"0.333333333", 100, New(1, 3), nil},
+		{"100000000000000000000", 1, Zero, rat128.ErrNumOverflow},
+		{"1/3", 0, Zero, rat128.ErrDenInvalid},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%s:%d", c.R, c.MaxDen), func(t *testing.T) {
+			r, _ := new(big.Rat).SetString(c.R)
+			z, err := rat128.ApproxBigRat(r, c.MaxDen)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if c.Err == nil && z != c.Z {
+				t.Errorf("got %v, want %v", z, c.Z)
+			}
+		})
+	}
+}
+
+func TestApproxPi(t *testing.T) {
+	cases := []struct {
+		MaxDen int64
+		Z      rat128.N
+	}{
+		{0, New(3, 1)},
+		{1, New(3, 1)},
+		{7, New(22, 7)},
+		{100, New(311, 99)},
+		{113, New(355, 113)},
+		{30000, New(94053, 29938)},
+		{33102, New(103993, 33102)},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprint(c.MaxDen), func(t *testing.T) {
+			if z := rat128.ApproxPi(c.MaxDen); z != c.Z {
+				t.Errorf("got %v, want %v", z, c.Z)
+			}
+		})
+	}
+	if z := rat128.ApproxPi(math.MaxInt64); !z.IsValid() || z.Den() > math.MaxInt64/4 {
+		t.Errorf("got invalid or out of range value %v", z)
+	}
+}
+
+func TestApproxE(t *testing.T) {
+	cases := []struct {
+		MaxDen int64
+		Z      rat128.N
+	}{
+		{1, New(3, 1)},
+		{4, New(11, 4)},
+		{7, New(19, 7)},
+		{100, New(193, 71)},
+		{1000, New(1457, 536)},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprint(c.MaxDen), func(t *testing.T) {
+			if z := rat128.ApproxE(c.MaxDen); z != c.Z {
+				t.Errorf("got %v, want %v", z, c.Z)
+			}
+		})
+	}
+	if z := rat128.ApproxE(math.MaxInt64); !z.IsValid() {
+		t.Errorf("got invalid value %v", z)
+	}
+}
+
+func TestN_Simplify(t *testing.T) {
+	cases := []struct {
+		X, Tol, Z rat128.N
+		Err       error
+	}{
+		{New(1, 3), New(0, 1), New(1, 3), nil},
+		{New(333, 1000), New(1, 1000), New(1, 3), nil},
+		{New(333, 1000), New(-1, 1000), New(1, 3), nil},
+		{New(333, 1000), New(1, 10000), New(257, 772), nil},
+		{New(333, 1000), New(1, 1000000), New(333, 1000), nil},
+		{New(3141593, 1000000), New(1, 1000000), New(355, 113), nil},
+		{New(3141593, 1000000), New(2, 1000), New(22, 7), nil},
+		{New(-3141593, 1000000), New(2, 1000), New(-22, 7), nil},
+		{New(1, 10), New(1, 5), New(0, 1), nil},
+		{New(7, 2), New(1, 2), New(3, 1), nil},
+		{New(5, 4), New(1, 4), New(1, 1), nil},
+		{New(7, 4), New(1, 4), New(2, 1), nil},
+		{New(7, 4), New(1, 5), New(5, 3), nil},
+		{New(math.MaxInt64, 1), New(1, 1), Zero, rat128.ErrNumOverflow},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s)~(%s)", c.X.RationalString("_"), c.Tol.RationalString("_")), func(t *testing.T) {
+			z, err := c.X.Simplify(c.Tol)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if c.Err == nil && z != c.Z {
+				t.Errorf("got %v, want %v", z, c.Z)
+			}
+		})
+	}
+}
+
+func TestN_ApproximateWithResidual(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		X               rat128.N
+		MaxDen          int64
+		Approx, Residue rat128.N
+		Err             error
+	}{
+		{New(3141593, 1000000), 7, New(22, 7), New(-8849, 7000000), nil},
+		{New(3141593, 1000000), 113, New(355, 113), New(9, 113000000), nil},
+		{New(3141593, 1000000), 1000, New(355, 113), New(9, 113000000), nil},
+		{New(3141593, 1000000), 1000000, New(3141593, 1000000), Zero, nil},
+		{New(-1, 3), 2, New(-1, 2), New(1, 6), nil},
+		{New(5, 1), 1, New(5, 1), Zero, nil},
+		{New(M-2, M), M - 1, Zero, Zero, rat128.ErrDenOverflow},
+		{New(1, 2), 0, Zero, Zero, rat128.ErrDenInvalid},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s)@%d", c.X.RationalString("_"), c.MaxDen), func(t *testing.T) {
+			approx, residual, err := c.X.ApproximateWithResidual(c.MaxDen)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if approx != c.Approx || residual != c.Residue {
+				t.Errorf("got (%v, %v), want (%v, %v)", approx, residual, c.Approx, c.Residue)
+			} else if err == nil && approx.Add(residual) != c.X {
+				t.Errorf("approx+residual == %v != %v", approx.Add(residual), c.X)
+			}
+		})
+	}
+}
+
+func TestN_CandidatesWithin(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		X, Tol     rat128.N
+		MaxDen     int64
+		Candidates []rat128.N
+		Err        error
+	}{
+		{New(33, 100), New(2, 100), 10, []rat128.N{New(1, 3)}, nil},
+		{New(33, 100), New(-5, 100), 12, []rat128.N{New(1, 3), New(2, 7), New(3, 8), New(3, 10), New(4, 11)}, nil},
+		{New(-7, 10), New(1, 10), 8, []rat128.N{New(-2, 3), New(-3, 4), New(-4, 5), New(-3, 5), New(-5, 7), New(-5, 8)}, nil},
+		{New(1, 2), Zero, 4, []rat128.N{New(1, 2)}, nil},
+		{New(1, 2), Zero, 1, nil, nil},
+		{New(1, 2), New(1, 2), 4, []rat128.N{New(0, 1), New(1, 1), New(1, 2), New(1, 3), New(2, 3), New(1, 4), New(3, 4)}, nil},
+		{New(314159, 100000), New(1, 100), 120, []rat128.N{
+			New(22, 7), New(47, 15), New(63, 20), New(69, 22), New(85, 27), New(91, 29), New(104, 33), New(107, 34),
+			New(113, 36), New(116, 37), New(129, 41), New(135, 43), New(148, 47), New(151, 48), New(157, 50), New(160, 51),
+		}, nil},
+		{New(1, M), New(1, M), M/2 + 2, []rat128.N{New(0, 1), New(1, M/2+1), New(1, M/2+2)}, nil},
+		{New(9, 10), New(1, 1), 3, []rat128.N{New(1, 1), New(0, 1), New(1, 2), New(3, 2), New(2, 3), New(4, 3), New(1, 3), New(5, 3)}, nil},
+		{New(-9, 10), New(1, 1), 3, []rat128.N{New(-1, 1), New(0, 1), New(-1, 2), New(-3, 2), New(-2, 3), New(-4, 3), New(-1, 3), New(-5, 3)}, nil},
+		{Zero, New(100, 1), 1, []rat128.N{
+			New(0, 1), New(-1, 1), New(1, 1), New(-2, 1), New(2, 1), New(-3, 1), New(3, 1), New(-4, 1),
+			New(4, 1), New(-5, 1), New(5, 1), New(-6, 1), New(6, 1), New(-7, 1), New(7, 1), New(-8, 1),
+		}, nil},
+		{New(1, 2), New(1, 3), 0, nil, rat128.ErrDenInvalid},
+		{New(M, 1), New(1, 1), 1, nil, rat128.ErrNumOverflow},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s)~(%s)@%d", c.X.RationalString("_"), c.Tol.RationalString("_"), c.MaxDen), func(t *testing.T) {
+			cs, err := c.X.CandidatesWithin(c.Tol, c.MaxDen)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if !reflect.DeepEqual(cs, c.Candidates) {
+				t.Errorf("got %v, want %v", cs, c.Candidates)
+			}
+		})
+	}
+	// compare against every fraction in the band, sorted
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		x := New(rng.Int63n(201)-100, rng.Int63n(20)+1)
+		tol := New(rng.Int63n(40), rng.Int63n(20)+1)
+		maxDen := rng.Int63n(12) + 1
+		var want []rat128.N
+		for d := int64(1); d <= maxDen; d++ {
+			for p := x.Sub(tol).MulInt64(d).Round(rat128.RoundCeiling).Num(); New(p, d).Lte(x.Add(tol)); p++ {
+				if New(p, d).Den() == d {
+					want = append(want, New(p, d))
+				}
+			}
+		}
+		sort.SliceStable(want, func(i, j int) bool {
+			a, b := want[i], want[j]
+			if a.Den() != b.Den() {
+				return a.Den() < b.Den()
+			}
+			if c := a.Sub(x).Abs().Cmp(b.Sub(x).Abs()); c != 0 {
+				return c < 0
+			}
+			return a.Lt(b)
+		})
+		if len(want) > 16 {
+			want = want[:16]
+		}
+		cs, err := x.CandidatesWithin(tol, maxDen)
+		if err != nil || !reflect.DeepEqual(cs, want) {
+			t.Errorf("(%v)~(%v)@%d: got (%v, %v), want %v", x, tol, maxDen, cs, err, want)
+		}
+	}
+}
@@ -0,0 +1,336 @@
+package rat128
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// ContinuedLogarithm returns the terms of the binary continued logarithm of
+// x, which must be positive. These are the integers a0, a1, ..., ak such that
+//
+//	x == 2^a0 + 2^a0/(2^a1 + 2^a1/(2^a2 + ... + 2^ak))
+//
+// The first term is negative if x < 1, but the rest are always non-negative.
+// Since x is rational, the expansion is always finite.
+// ContinuedLogarithm returns ErrInvalid if x is not positive.
+func (x N) ContinuedLogarithm() ([]int, error) {
+	if x.Sign() <= 0 {
+		return nil, ErrInvalid
+	}
+	var terms []int
+	p, q := x.Num(), x.Den()
+	for {
+		// a = floor(log2(p/q)), found by comparing bit lengths and then
+		// correcting for the leading bits
+		a := bits.Len64(uint64(p)) - bits.Len64(uint64(q))
+		if a >= 0 {
+			if p < q<<a {
+				a--
+			}
+		} else if p<<-a < q {
+			a--
+		}
+		terms = append(terms, a)
+		// now x = 2^a*y for y in [1, 2), and x' = 1/(y-1) is the remainder;
+		// p<<-a < 2*q < 2^64, so it can be done in uint64
+		var num, den uint64
+		if a >= 0 {
+			num, den = uint64(q)<<a, uint64(p)-uint64(q)<<a
+		} else {
+			num, den = uint64(q), uint64(p)<<-a-uint64(q)
+		}
+		if den == 0 {
+			return terms, nil
+		}
+		// both num and den are no larger than max(p, q) so they fit
+		d := GCD(int64(num), int64(den))
+		p, q = int64(num)/d, int64(den)/d
+	}
+}
+
+// FromContinuedLogarithm returns the rational number whose binary continued
+// logarithm has the given terms, as described by N.ContinuedLogarithm.
+// FromContinuedLogarithm returns ErrEmpty if there are no terms and an
+// overflow error if the result or an intermediate value does not fit.
+func FromContinuedLogarithm(terms []int) (N, error) {
+	if len(terms) == 0 {
+		return N{}, ErrEmpty
+	}
+	// evaluate from the innermost term out, with x = 2^a*(1+1/x) each step;
+	// x = p/q is kept in reduced form, and p+q < 2^64 can't overflow uint64
+	p, q := uint64(1), uint64(1)
+	for i := len(terms) - 1; i >= 0; i-- {
+		if i < len(terms)-1 {
+			// 1+1/(p/q) = (p+q)/p, which is reduced since p and q are coprime
+			p, q = p+q, p
+		}
+		// p/q >= 1 here, so 2^a*p/q can't fit if |a| > 63, and rejecting
+		// those terms up front keeps the shifts below in range
+		a := terms[i]
+		if a > 63 {
+			return N{}, fmt.Errorf("evaluating term %d: %w", i, ErrNumOverflow)
+		} else if a < -63 {
+			return N{}, fmt.Errorf("evaluating term %d: %w", i, ErrDenOverflow)
+		}
+		// multiply by 2^a, dividing out any factors of 2 first; the only
+		// factors that can be shared are powers of 2, since p and q are
+		// coprime
+		if a >= 0 {
+			tz := min(bits.TrailingZeros64(q), a)
+			q >>= tz
+			if a -= tz; bits.Len64(p)+a > 63 {
+				return N{}, fmt.Errorf("evaluating term %d: %w", i, ErrNumOverflow)
+			}
+			p <<= a
+		} else {
+			tz := min(bits.TrailingZeros64(p), -a)
+			p >>= tz
+			if a += tz; bits.Len64(q)-a > 63 {
+				return N{}, fmt.Errorf("evaluating term %d: %w", i, ErrDenOverflow)
+			}
+			q <<= -a
+		}
+	}
+	if p > math.MaxInt64 {
+		return N{}, ErrNumOverflow
+	}
+	return tryAlreadyReduced(int64(p), int64(q))
+}
+
+// ContinuedFraction returns the terms of the simple continued fraction of x.
+// These are the integers a0, a1, ..., ak such that
+//
+//	x == a0 + 1/(a1 + 1/(a2 + ... + 1/ak))
+//
+// The first term is floor(x), so it is negative if x < 0, but the rest are
+// always positive. The last term is greater than 1 unless x is an integer,
+// making the expansion unique.
+func (x N) ContinuedFraction() []int64 {
+	var terms []int64
+	x.ContinuedFractionSeq()(func(a int64) bool {
+		terms = append(terms, a)
+		return true
+	})
+	return terms
+}
+
+// ContinuedFractionSeq returns a sequence which yields the terms of the simple
+// continued fraction of x, as described by ContinuedFraction, one at a time.
+// Each term is computed only when needed, so the caller can stop early, as
+// soon as a convergent is good enough, by returning false from yield.
+// The signature matches iter.Seq[int64], so the sequence can be used with
+// range-over-func where that is supported.
+func (x N) ContinuedFractionSeq() func(yield func(int64) bool) {
+	return func(yield func(int64) bool) {
+		m, n := x.Num(), x.Den()
+		for {
+			a, r := m/n, m%n
+			if r < 0 {
+				a, r = a-1, r+n
+			}
+			if !yield(a) || r == 0 {
+				return
+			}
+			m, n = n, r
+		}
+	}
+}
+
+// FromContinuedFraction returns the rational number whose simple continued
+// fraction has the given terms, as described by N.ContinuedFraction.
+// The terms need not be in canonical form, but FromContinuedFraction returns
+// ErrDivByZero if they evaluate to 1/0 at any level.
+// FromContinuedFraction returns ErrEmpty if there are no terms and an
+// overflow error if the result or an intermediate convergent does not fit.
+func FromContinuedFraction(terms []int64) (N, error) {
+	if len(terms) == 0 {
+		return N{}, ErrEmpty
+	}
+	// convergents h/k satisfy h[i] = a[i]*h[i-1] + h[i-2] and likewise for k,
+	// starting from h[-1]/k[-1] = 1/0 and h[-2]/k[-2] = 0/1
+	h0, h1 := int64(0), int64(1)
+	k0, k1 := int64(1), int64(0)
+	for i, a := range terms {
+		h, ok := addMul64(h0, a, h1)
+		if !ok {
+			return N{}, fmt.Errorf("evaluating term %d: %w", i, ErrNumOverflow)
+		}
+		k, ok := addMul64(k0, a, k1)
+		if !ok {
+			return N{}, fmt.Errorf("evaluating term %d: %w", i, ErrDenOverflow)
+		}
+		h0, h1, k0, k1 = h1, h, k1, k
+	}
+	if k1 == 0 {
+		return N{}, ErrDivByZero
+	}
+	if k1 < 0 {
+		if k1 == math.MinInt64 || h1 == math.MinInt64 {
+			return N{}, ErrNumOverflow
+		}
+		h1, k1 = -h1, -k1
+	}
+	return Try(h1, k1)
+}
+
+// ContinuedFractionString returns the simple continued fraction of x in the
+// bracket notation "[a0; a1, a2, ..., ak]", or "[a0]" if x is an integer.
+func (x N) ContinuedFractionString() string {
+	terms := x.ContinuedFraction()
+	var buf strings.Builder
+	buf.WriteByte('[')
+	buf.WriteString(strconv.FormatInt(terms[0], 10))
+	for i, a := range terms[1:] {
+		if i == 0 {
+			buf.WriteString("; ")
+		} else {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(strconv.FormatInt(a, 10))
+	}
+	buf.WriteByte(']')
+	return buf.String()
+}
+
+// ParseContinuedFractionString parses a continued fraction in the bracket
+// notation produced by ContinuedFractionString and evaluates it with
+// FromContinuedFraction. Spaces around the terms are ignored.
+// It returns ErrFmtInvalid if s is not in the bracket notation.
+func ParseContinuedFractionString(s string) (N, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 || s[0] != '[' || s[len(s)-1] != ']' {
+		return N{}, ErrFmtInvalid
+	}
+	first, rest, hasRest := strings.Cut(s[1:len(s)-1], ";")
+	fields := []string{first}
+	if hasRest {
+		fields = append(fields, strings.Split(rest, ",")...)
+	}
+	terms := make([]int64, len(fields))
+	for i, f := range fields {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			return N{}, ErrFmtInvalid
+		}
+		var err error
+		if terms[i], err = strconv.ParseInt(f, 10, 64); err != nil {
+			return N{}, fmt.Errorf("parsing term %d: %w", i, ErrFmtInvalid)
+		}
+	}
+	return FromContinuedFraction(terms)
+}
+
+// FromGeneralizedContinuedFraction returns the value of the generalized
+// continued fraction
+//
+//	b[0] + a[0]/(b[1] + a[1]/(b[2] + ... + a[k-1]/b[k]))
+//
+// where the partial numerators a may be any integers, including negative
+// ones, so that len(b) == len(a)+1. The value is computed from the innermost
+// term outward, reducing at each step.
+// FromGeneralizedContinuedFraction returns ErrEmpty if b is empty, ErrInvalid
+// if the lengths do not match, ErrDivByZero if a partial denominator
+// evaluates to zero, and an overflow error if an intermediate value does not
+// fit.
+func FromGeneralizedContinuedFraction(a, b []int64) (N, error) {
+	if len(b) == 0 {
+		return N{}, ErrEmpty
+	} else if len(b) != len(a)+1 {
+		return N{}, ErrInvalid
+	}
+	k := len(a)
+	x, err := Try(b[k], 1)
+	if err != nil {
+		return N{}, fmt.Errorf("evaluating term %d: %w", k, err)
+	}
+	for i := k - 1; i >= 0; i-- {
+		if x.IsZero() {
+			return N{}, fmt.Errorf("evaluating term %d: %w", i, ErrDivByZero)
+		}
+		if x, err = x.TryInv(); err == nil {
+			if x, err = x.TryMulInt64(a[i]); err == nil {
+				x, err = x.TryAddInt64(b[i])
+			}
+		}
+		if err != nil {
+			return N{}, fmt.Errorf("evaluating term %d: %w", i, err)
+		}
+	}
+	return x, nil
+}
+
+// ApproximationQuality returns the largest partial quotient a1, a2, ... in
+// the simple continued fraction of x, ignoring the integer part a0, or 0 if
+// x is an integer. A large partial quotient ak means that the convergent
+// before it is an unusually good approximation of x for the size of its
+// denominator; for example, 355/113 is so close to 103993/33102 because the
+// next partial quotient is 292.
+// ApproximationQuality returns an error only if x is not valid, as reported
+// by CheckInvariants.
+func (x N) ApproximationQuality() (N, error) {
+	if err := CheckInvariants(x); err != nil {
+		return N{}, err
+	}
+	var best int64
+	first := true
+	x.ContinuedFractionSeq()(func(a int64) bool {
+		if !first && a > best {
+			best = a
+		}
+		first = false
+		return true
+	})
+	return N{best, 0}, nil
+}
+
+// ConvergentStepper produces the convergents of the continued fraction of a
+// target one at a time. See NewConvergentStepper for details.
+type ConvergentStepper struct {
+	// num/den is what remains of the target to be expanded, and p0/q0 and
+	// p1/q1 are the last two convergents
+	num, den       big.Int
+	p0, q0, p1, q1 big.Int
+}
+
+// NewConvergentStepper returns a stepper over the convergents of the simple
+// continued fraction of target, which are successively better rational
+// approximations of it. For example, the convergents of 3.14159 are 3, 22/7,
+// 333/106, 355/113, and so on. The stepper keeps its own copy of target.
+func NewConvergentStepper(target *big.Rat) *ConvergentStepper {
+	s := new(ConvergentStepper)
+	s.num.Set(target.Num())
+	s.den.Set(target.Denom())
+	s.q0.SetInt64(1)
+	s.p1.SetInt64(1)
+	return s
+}
+
+// Next returns the next convergent and true, or else returns 0 and false if
+// the previous convergent was equal to the target or the next one does not
+// fit in N. Once Next has returned false, it always does.
+func (s *ConvergentStepper) Next() (N, bool) {
+	if s.den.Sign() == 0 {
+		return N{}, false
+	}
+	var a, r, p2, q2 big.Int
+	a.DivMod(&s.num, &s.den, &r)
+	s.num.Set(&s.den)
+	s.den.Set(&r)
+	// p2/q2 = (a*p1 + p0) / (a*q1 + q0)
+	p2.Add(p2.Mul(&a, &s.p1), &s.p0)
+	q2.Add(q2.Mul(&a, &s.q1), &s.q0)
+	s.p0.Set(&s.p1)
+	s.q0.Set(&s.q1)
+	s.p1.Set(&p2)
+	s.q1.Set(&q2)
+	if !s.p1.IsInt64() || s.p1.Int64() == math.MinInt64 || !s.q1.IsInt64() {
+		s.den.SetInt64(0)
+		return N{}, false
+	}
+	// successive convergents are always in lowest terms
+	return N{s.p1.Int64(), s.q1.Int64() - 1}, true
+}
@@ -0,0 +1,321 @@
+package rat128_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/kbolino/rat128"
+)
+
+func TestN_ContinuedLogarithm(t *testing.T) {
+	cases := []struct {
+		X     rat128.N
+		Terms []int
+		Err   error
+	}{
+		{New(1, 1), []int{0}, nil},
+		{New(2, 1), []int{1}, nil},
+		{New(1, 4), []int{-2}, nil},
+		{New(3, 1), []int{1, 1}, nil},
+		{New(3, 2), []int{0, 1}, nil},
+		{New(5, 3), []int{0, 0, 1}, nil},
+		{New(3, 8), []int{-2, 1}, nil},
+		{New(7, 1), []int{2, 0, 1, 1}, nil},
+		{New(math.MaxInt64, 1), nil, nil},
+		{New(1, math.MaxInt64), nil, nil},
+		{New(0, 1), nil, rat128.ErrInvalid},
+		{New(-1, 1), nil, rat128.ErrInvalid},
+	}
+	for _, c := range cases {
+		t.Run(c.X.String(), func(t *testing.T) {
+			terms, err := c.X.ContinuedLogarithm()
+			if err != c.Err {
+				t.Fatalf("got error %v, want %v", err, c.Err)
+			} else if err != nil {
+				return
+			}
+			if c.Terms != nil && fmt.Sprint(terms) != fmt.Sprint(c.Terms) {
+				t.Errorf("got %v, want %v", terms, c.Terms)
+			}
+			x, err := rat128.FromContinuedLogarithm(terms)
+			if err != nil {
+				t.Fatalf("got unexpected error %v from %v", err, terms)
+			} else if x != c.X {
+				t.Errorf("got %v after round trip, want %v", x, c.X)
+			}
+		})
+	}
+}
+
+func TestFromContinuedLogarithm(t *testing.T) {
+	cases := []struct {
+		Terms []int
+		X     rat128.N
+		Err   error
+	}{
+		{nil, Zero, rat128.ErrEmpty},
+		{[]int{0}, New(1, 1), nil},
+		{[]int{2, 0, 1, 1}, New(7, 1), nil},
+		{[]int{2, 1, 1}, New(16, 3), nil},
+		{[]int{62}, New(1<<62, 1), nil},
+		{[]int{63}, Zero, rat128.ErrNumOverflow},
+		{[]int{-62}, New(1, 1<<62), nil},
+		{[]int{-63}, Zero, rat128.ErrDenOverflow},
+		{[]int{62, 0}, Zero, rat128.ErrNumOverflow},
+		{[]int{64}, Zero, rat128.ErrNumOverflow},
+		{[]int{-64}, Zero, rat128.ErrDenOverflow},
+		{[]int{math.MaxInt}, Zero, rat128.ErrNumOverflow},
+		{[]int{math.MinInt}, Zero, rat128.ErrDenOverflow},
+		{[]int{-math.MaxInt}, Zero, rat128.ErrDenOverflow},
+		{[]int{1, math.MaxInt}, Zero, rat128.ErrNumOverflow},
+		{[]int{-70, 0, 1}, Zero, rat128.ErrDenOverflow},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprint(c.Terms), func(t *testing.T) {
+			x, err := rat128.FromContinuedLogarithm(c.Terms)
+			if !errors.Is(err, c.Err) {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if c.Err == nil && x != c.X {
+				t.Errorf("got %v, want %v", x, c.X)
+			}
+		})
+	}
+}
+
+func TestContinuedLogarithmRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x := rat128.RandN(r).Abs()
+		if x.IsZero() {
+			continue
+		}
+		terms, err := x.ContinuedLogarithm()
+		if err != nil {
+			t.Fatalf("(%v).ContinuedLogarithm(): got unexpected error %v", x, err)
+		}
+		y, err := rat128.FromContinuedLogarithm(terms)
+		if err != nil {
+			t.Fatalf("FromContinuedLogarithm(%v): got unexpected error %v", terms, err)
+		} else if y != x {
+			t.Errorf("FromContinuedLogarithm(%v): got %v, want %v", terms, y, x)
+		}
+	}
+}
+
+func TestN_ContinuedFractionString(t *testing.T) {
+	cases := []struct {
+		X      rat128.N
+		String string
+	}{
+		{New(0, 1), "[0]"},
+		{New(-3, 1), "[-3]"},
+		{New(1, 2), "[0; 2]"},
+		{New(-1, 2), "[-1; 2]"},
+		{New(355, 113), "[3; 7, 16]"},
+		{New(103993, 33102), "[3; 7, 15, 1, 292]"},
+		{New(-7, 3), "[-3; 1, 2]"},
+		{New(math.MaxInt64, math.MaxInt64-1), "[1; 9223372036854775806]"},
+	}
+	for _, c := range cases {
+		t.Run(c.X.String(), func(t *testing.T) {
+			s := c.X.ContinuedFractionString()
+			if s != c.String {
+				t.Errorf("got %s, want %s", s, c.String)
+			}
+			x, err := rat128.ParseContinuedFractionString(s)
+			if err != nil {
+				t.Fatalf("got unexpected error %v from %s", err, s)
+			} else if x != c.X {
+				t.Errorf("got %v after round trip, want %v", x, c.X)
+			}
+		})
+	}
+}
+
+func TestParseContinuedFractionString(t *testing.T) {
+	cases := []struct {
+		S   string
+		X   rat128.N
+		Err error
+	}{
+		{"[3; 7, 15, 1]", New(355, 113), nil},
+		{" [3;7,15,1] ", New(355, 113), nil},
+		{"[0; 1, 1]", New(1, 2), nil},
+		{"[2; -2]", New(3, 2), nil},
+		{"[1; 0]", Zero, rat128.ErrDivByZero},
+		{"[1; 9223372036854775807, 2]", Zero, rat128.ErrNumOverflow},
+		{"", Zero, rat128.ErrFmtInvalid},
+		{"[]", Zero, rat128.ErrFmtInvalid},
+		{"3; 7", Zero, rat128.ErrFmtInvalid},
+		{"[3; 7,]", Zero, rat128.ErrFmtInvalid},
+		{"[3, 7]", Zero, rat128.ErrFmtInvalid},
+		{"[3; x]", Zero, rat128.ErrFmtInvalid},
+	}
+	for _, c := range cases {
+		t.Run(c.S, func(t *testing.T) {
+			x, err := rat128.ParseContinuedFractionString(c.S)
+			if !errors.Is(err, c.Err) {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if c.Err == nil && x != c.X {
+				t.Errorf("got %v, want %v", x, c.X)
+			}
+		})
+	}
+}
+
+func TestContinuedFractionRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x := rat128.RandN(r)
+		terms := x.ContinuedFraction()
+		for j, a := range terms[1:] {
+			if a <= 0 {
+				t.Fatalf("%v: term %d is %d, want positive", x, j+1, a)
+			}
+		}
+		y, err := rat128.FromContinuedFraction(terms)
+		if err != nil {
+			t.Fatalf("%v: got unexpected error %v from %v", x, err, terms)
+		} else if y != x {
+			t.Fatalf("%v: got %v after round trip", x, y)
+		}
+	}
+}
+
+func TestN_ContinuedFractionSeq(t *testing.T) {
+	x := New(103993, 33102)
+	var terms []int64
+	x.ContinuedFractionSeq()(func(a int64) bool {
+		terms = append(terms, a)
+		return true
+	})
+	if fmt.Sprint(terms) != "[3 7 15 1 292]" {
+		t.Errorf("got %v, want [3 7 15 1 292]", terms)
+	}
+	// stop once the convergent is within 1/100, which is 22/7
+	var h0, h1, k0, k1 int64 = 0, 1, 1, 0
+	calls := 0
+	x.ContinuedFractionSeq()(func(a int64) bool {
+		calls++
+		h0, h1 = h1, a*h1+h0
+		k0, k1 = k1, a*k1+k0
+		d := x.Sub(New(h1, k1)).Abs()
+		return d.Cmp(New(1, 100)) >= 0
+	})
+	if calls != 2 || New(h1, k1) != New(22, 7) {
+		t.Errorf("got %d/%d after %d calls, want 22/7 after 2 calls", h1, k1, calls)
+	}
+}
+
+func TestFromGeneralizedContinuedFraction(t *testing.T) {
+	cases := []struct {
+		Name string
+		A, B []int64
+		X    rat128.N
+		Err  error
+	}{
+		{"Integer", nil, []int64{5}, New(5, 1), nil},
+		{"Simple", []int64{1, 1, 1}, []int64{3, 7, 15, 1}, New(355, 113), nil},
+		// tan(1) ≈ 1/(1 - 1/(3 - 1/(5 - 1/7)))
+		{"Tan", []int64{1, -1, -1, -1}, []int64{0, 1, 3, 5, 7}, New(95, 61), nil},
+		// 4/π ≈ 1 + 1²/(3 + 2²/(5 + 3²/7))
+		{"Pi", []int64{1, 4, 9}, []int64{1, 3, 5, 7}, New(51, 40), nil},
+		{"DivByZero", []int64{1, -1}, []int64{0, 1, 1}, Zero, rat128.ErrDivByZero},
+		{"Overflow", []int64{math.MaxInt64}, []int64{1, 1}, Zero, rat128.ErrNumOverflow},
+		{"Empty", nil, nil, Zero, rat128.ErrEmpty},
+		{"Mismatch", []int64{1, 1}, []int64{1, 1}, Zero, rat128.ErrInvalid},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			x, err := rat128.FromGeneralizedContinuedFraction(c.A, c.B)
+			if !errors.Is(err, c.Err) {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if x != c.X {
+				t.Errorf("got %v, want %v", x, c.X)
+			}
+		})
+	}
+}
+
+func TestN_ApproximationQuality(t *testing.T) {
+	cases := []struct {
+		X, Q rat128.N
+	}{
+		{New(0, 1), New(0, 1)},
+		{New(-7, 1), New(0, 1)},
+		{New(1, 2), New(2, 1)},
+		{New(1000, 7), New(6, 1)},
+		{New(103993, 33102), New(292, 1)},
+		{New(-103993, 33102), New(292, 1)},
+		{New(1, 1000), New(1000, 1)},
+		{New(55, 89), New(2, 1)},
+		{New(math.MaxInt64, math.MaxInt64-1), New(math.MaxInt64-1, 1)},
+	}
+	for _, c := range cases {
+		t.Run(c.X.String(), func(t *testing.T) {
+			q, err := c.X.ApproximationQuality()
+			if err != nil {
+				t.Errorf("got unexpected error %v", err)
+			} else if q != c.Q {
+				t.Errorf("got %v, want %v", q, c.Q)
+			}
+		})
+	}
+}
+
+func TestConvergentStepper(t *testing.T) {
+	const pi = "3.14159265358979323846264338327950288419716939937510582097494459"
+	cases := []struct {
+		Target      string
+		Convergents []rat128.N
+	}{
+		{"3.14159", []rat128.N{
+			New(3, 1), New(22, 7), New(333, 106), New(355, 113), New(9208, 2931),
+			New(9563, 3044), New(76149, 24239), New(314159, 100000),
+		}},
+		{"-1/2", []rat128.N{New(-1, 1), New(-1, 2)}},
+		{"1/3", []rat128.N{New(0, 1), New(1, 3)}},
+		{"5", []rat128.N{New(5, 1)}},
+		{"0", []rat128.N{New(0, 1)}},
+		{"9223372036854775807", []rat128.N{New(math.MaxInt64, 1)}},
+		{"9223372036854775808", nil},
+		{"-9223372036854775808", nil},
+	}
+	for _, c := range cases {
+		t.Run(c.Target, func(t *testing.T) {
+			r, _ := new(big.Rat).SetString(c.Target)
+			s := rat128.NewConvergentStepper(r)
+			r.SetInt64(0) // the stepper must not depend on r after creation
+			var got []rat128.N
+			for x, ok := s.Next(); ok; x, ok = s.Next() {
+				got = append(got, x)
+			}
+			if !reflect.DeepEqual(got, c.Convergents) {
+				t.Errorf("got %v, want %v", got, c.Convergents)
+			}
+			if x, ok := s.Next(); ok {
+				t.Errorf("got %v after end", x)
+			}
+		})
+	}
+	t.Run("Pi", func(t *testing.T) {
+		r, _ := new(big.Rat).SetString(pi)
+		s := rat128.NewConvergentStepper(r)
+		var n int
+		var last rat128.N
+		for x, ok := s.Next(); ok; x, ok = s.Next() {
+			if err := rat128.CheckInvariants(x); err != nil {
+				t.Fatalf("convergent %d: %v", n, err)
+			}
+			n, last = n+1, x
+		}
+		if want := New(2646693125139304345, 842468587426513207); n != 33 || last != want {
+			t.Errorf("got %d convergents ending with %v, want 33 ending with %v", n, last, want)
+		}
+	})
+}
@@ -1,5 +1,10 @@
 package rat128
 
+import (
+	"math"
+	"math/bits"
+)
+
 // GCD returns the greatest common denominator (GCD) of m and n.
 // The GCD is the largest integer that divides both m and n.
 func GCD(m, n int64) int64 {
@@ -35,3 +40,96 @@ func ExtGCD(m, n int64) (a, b, d int64) {
 		b = t - q*b
 	}
 }
+
+// ModInt returns x reduced modulo p, treating x as Num() times the modular
+// multiplicative inverse of Den(); that is, it returns the k in [0, p) such
+// that k*Den() ≡ Num() (mod p).
+// ModInt returns ErrInvalid if p < 2 and ErrDivByZero if Den() has no inverse
+// modulo p, which happens when they share a common factor.
+func (x N) ModInt(p int64) (int64, error) {
+	if p < 2 {
+		return 0, ErrInvalid
+	}
+	inv, _, d := ExtGCD(x.Den()%p, p)
+	if d != 1 {
+		return 0, ErrDivByZero
+	}
+	// normalize both factors into [0, p) and multiply them with wide
+	// arithmetic, since their product may overflow
+	if inv %= p; inv < 0 {
+		inv += p
+	}
+	m := x.Num() % p
+	if m < 0 {
+		m += p
+	}
+	hi, lo := bits.Mul64(uint64(m), uint64(inv))
+	return int64(bits.Rem64(hi, lo, uint64(p))), nil
+}
+
+// FareyNeighbors returns the fractions immediately before and after x among
+// all fractions with denominators no greater than order; when 0 <= x <= 1,
+// these are x's neighbors in the Farey sequence of that order.
+// The neighbors are found from the Bézout coefficients of Num() and Den(),
+// since for adjacent fractions p/q < r/s it is always the case that
+// r*q - p*s == 1.
+// FareyNeighbors returns ErrInvalid if Den() > order, and ErrNumOverflow if a
+// neighbor's numerator does not fit.
+func (x N) FareyNeighbors(order int64) (left, right N, err error) {
+	a, b := x.Num(), x.Den()
+	if b > order {
+		return N{}, N{}, ErrInvalid
+	}
+	if a == 0 {
+		return N{-1, order - 1}, N{1, order - 1}, nil
+	}
+	am := a % b
+	if am < 0 {
+		am += b
+	}
+	inv, _, _ := ExtGCD(am, b)
+	if inv %= b; inv < 0 {
+		inv += b
+	}
+	// left = p/q with a*q - b*p == 1, so q ≡ inv (mod b);
+	// right = r/s with b*r - a*s == 1, so s ≡ -inv (mod b);
+	// in both cases, the largest denominator no greater than order is chosen
+	q := inv + b*((order-inv)/b)
+	s0 := (b - inv) % b
+	s := s0 + b*((order-s0)/b)
+	p, ok := fareyNum(a, q, 1, b)
+	if !ok {
+		return N{}, N{}, ErrNumOverflow
+	}
+	r, ok := fareyNum(a, s, -1, b)
+	if !ok {
+		return N{}, N{}, ErrNumOverflow
+	}
+	return N{p, q - 1}, N{r, s - 1}, nil
+}
+
+// fareyNum returns (a*q - c)/b, which must be exact, for nonzero a, q > 0,
+// c = ±1, and b > 0, along with false if it overflows.
+func fareyNum(a, q, c, b int64) (int64, bool) {
+	hi, lo := bits.Mul64(uint64(abs64(a)), uint64(q))
+	// a*q - c has the same sign as a, so work with its magnitude
+	var carry uint64
+	if (a > 0) == (c > 0) {
+		lo, carry = bits.Sub64(lo, 1, 0)
+		hi -= carry
+	} else {
+		lo, carry = bits.Add64(lo, 1, 0)
+		hi += carry
+	}
+	if hi >= uint64(b) {
+		return 0, false
+	}
+	quo, _ := bits.Div64(hi, lo, uint64(b))
+	if quo > math.MaxInt64 {
+		return 0, false
+	}
+	if a < 0 {
+		return -int64(quo), true
+	}
+	return int64(quo), true
+}
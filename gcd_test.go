@@ -67,3 +67,70 @@ func TestExtGCD(t *testing.T) {
 		})
 	}
 }
+
+func TestN_ModInt(t *testing.T) {
+	const P = 1_000_000_007
+	cases := []struct {
+		X   rat128.N
+		P   int64
+		K   int64
+		Err error
+	}{
+		{New(0, 1), 7, 0, nil},
+		{New(3, 1), 7, 3, nil},
+		{New(10, 1), 7, 3, nil},
+		{New(-1, 1), 7, 6, nil},
+		{New(1, 2), 7, 4, nil},
+		{New(-1, 2), 7, 3, nil},
+		{New(2, 3), 7, 3, nil},
+		{New(1, 3), 10, 7, nil},
+		{New(1, 2), P, 500_000_004, nil},
+		{New(math.MaxInt64, math.MaxInt64-1), P, 928803248, nil},
+		{New(1, 7), 7, 0, rat128.ErrDivByZero},
+		{New(1, 2), 10, 0, rat128.ErrDivByZero},
+		{New(1, 2), 1, 0, rat128.ErrInvalid},
+		{New(1, 2), -7, 0, rat128.ErrInvalid},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s)%%%d", c.X.RationalString("_"), c.P), func(t *testing.T) {
+			k, err := c.X.ModInt(c.P)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if k != c.K {
+				t.Errorf("got %d, want %d", k, c.K)
+			}
+		})
+	}
+}
+
+func TestN_FareyNeighbors(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		X           rat128.N
+		Order       int64
+		Left, Right rat128.N
+		Err         error
+	}{
+		{New(1, 2), 5, New(2, 5), New(3, 5), nil},
+		{New(3, 7), 10, New(2, 5), New(4, 9), nil},
+		{New(-3, 7), 10, New(-4, 9), New(-2, 5), nil},
+		{New(5, 1), 4, New(19, 4), New(21, 4), nil},
+		{New(0, 1), 3, New(-1, 3), New(1, 3), nil},
+		{New(22, 7), 100, New(311, 99), New(305, 97), nil},
+		{New(1, 3), 3, New(0, 1), New(1, 2), nil},
+		{New(1, 1), M - 1, New(M-2, M-1), New(M, M-1), nil},
+		{New(1, 1), M, Zero, Zero, rat128.ErrNumOverflow},
+		{New(1, 3), 2, Zero, Zero, rat128.ErrInvalid},
+		{New(M-2, 2), 4, Zero, Zero, rat128.ErrNumOverflow},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%s@%d", c.X.RationalString("_"), c.Order), func(t *testing.T) {
+			left, right, err := c.X.FareyNeighbors(c.Order)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if left != c.Left || right != c.Right {
+				t.Errorf("got (%v, %v), want (%v, %v)", left, right, c.Left, c.Right)
+			}
+		})
+	}
+}
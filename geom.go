@@ -0,0 +1,180 @@
+package rat128
+
+import "math/big"
+
+// Orient2D returns the orientation of the points a, b, c as the sign of the
+// cross product (b-a)×(c-a): 1 if they turn counterclockwise, -1 if they turn
+// clockwise, and 0 if they are collinear. The result is exact; if a
+// difference or product overflows, Orient2D returns an error instead.
+func Orient2D(ax, ay, bx, by, cx, cy N) (int, error) {
+	var d [4]N
+	for i, pair := range [4][2]N{{bx, ax}, {cy, ay}, {by, ay}, {cx, ax}} {
+		var err error
+		if d[i], err = pair[0].TrySub(pair[1]); err != nil {
+			return 0, err
+		}
+	}
+	p, err := d[0].TryMul(d[1])
+	if err != nil {
+		return 0, err
+	}
+	q, err := d[2].TryMul(d[3])
+	if err != nil {
+		return 0, err
+	}
+	// comparing instead of subtracting avoids one more overflow
+	return p.Cmp(q), nil
+}
+
+// CircleParam returns the point on the unit circle given by the tangent
+// half-angle substitution with parameter t, which is
+//
+//	((1-t²)/(1+t²), 2t/(1+t²))
+//
+// Every rational point on the unit circle except (-1, 0) arises this way, so
+// this can be used to enumerate them. CircleParam returns an overflow error
+// if a coordinate or an intermediate value does not fit.
+func (t N) CircleParam() (x, y N, err error) {
+	t2, err := t.TrySqr()
+	if err != nil {
+		return N{}, N{}, err
+	}
+	d, err := t2.TryAddInt64(1)
+	if err != nil {
+		return N{}, N{}, err
+	}
+	if x, err = t2.Neg().TryAddInt64(1); err == nil {
+		x, err = x.TryDiv(d)
+	}
+	if err != nil {
+		return N{}, N{}, err
+	}
+	if y, err = t.TryMulInt64(2); err == nil {
+		y, err = y.TryDiv(d)
+	}
+	if err != nil {
+		return N{}, N{}, err
+	}
+	return x, y, nil
+}
+
+// CircularDistance returns the signed shortest distance from x to y around
+// a circle of the given circumference, after reducing both modulo the
+// circumference. The result lies in (-circumference/2, circumference/2], so
+// that it is positive when y is ahead of x by up to half the circumference.
+// CircularDistance returns ErrInvalid if circumference is not positive, and
+// an overflow error if an intermediate value does not fit.
+func (x N) CircularDistance(y, circumference N) (N, error) {
+	if circumference.Sign() <= 0 {
+		return N{}, ErrInvalid
+	}
+	a, err := x.TryMod(circumference)
+	if err != nil {
+		return N{}, err
+	}
+	b, err := y.TryMod(circumference)
+	if err != nil {
+		return N{}, err
+	}
+	// a and b are in [0, c), so d = b-a is in (-c, c), and the other way
+	// around the circle is e = d-c or d+c; rather than halving c, compare
+	// the magnitudes of d and e to pick the shorter way
+	d, err := b.TrySub(a)
+	if err != nil {
+		return N{}, err
+	}
+	switch d.Sign() {
+	case 1:
+		e, err := d.TrySub(circumference)
+		if err != nil {
+			return N{}, err
+		}
+		if d.Cmp(e.Neg()) > 0 {
+			return e, nil
+		}
+	case -1:
+		e, err := d.TryAdd(circumference)
+		if err != nil {
+			return N{}, err
+		}
+		if d.Neg().Cmp(e) >= 0 {
+			return e, nil
+		}
+	}
+	return d, nil
+}
+
+// Centroid returns the centroid of points, which is the mean of their x
+// coordinates and the mean of their y coordinates. Each coordinate is summed
+// exactly as by SumExact, so only the means themselves need to fit.
+// Centroid returns ErrDivByZero if there are no points, and an overflow error
+// if a mean does not fit.
+func Centroid(points [][2]N) ([2]N, error) {
+	if len(points) == 0 {
+		return [2]N{}, ErrDivByZero
+	}
+	var c [2]N
+	k := int64(len(points))
+	coords := make([]N, len(points))
+	for j := range c {
+		for i, p := range points {
+			coords[i] = p[j]
+		}
+		sum, r := SumOrBig(coords)
+		var err error
+		if r == nil {
+			if c[j], err = sum.TryDivInt64(k); err == nil {
+				continue
+			}
+			r = sum.BigRat()
+		}
+		if c[j], err = FromBigRat(r.Quo(r, new(big.Rat).SetInt64(k))); err != nil {
+			return [2]N{}, err
+		}
+	}
+	return c, nil
+}
+
+// PolygonArea returns the signed area of the polygon with the given vertices,
+// computed with the shoelace formula. The area is positive if the vertices go
+// around counterclockwise and negative if they go clockwise. The polygon is
+// closed implicitly, so the last vertex should not repeat the first. Fewer
+// than three vertices have an area of zero.
+// The cross products are summed exactly as by SumExact, and are themselves
+// computed with big.Rat if necessary, so PolygonArea only returns an overflow
+// error if the area does not fit.
+func PolygonArea(vertices [][2]N) (N, error) {
+	terms := make([]N, 0, 2*len(vertices))
+	for i, a := range vertices {
+		b := vertices[(i+1)%len(vertices)]
+		p, err := a[0].TryMul(b[1])
+		if err != nil {
+			return FromBigRat(polygonAreaBig(vertices))
+		}
+		q, err := a[1].TryMul(b[0])
+		if err != nil {
+			return FromBigRat(polygonAreaBig(vertices))
+		}
+		terms = append(terms, p, q.Neg())
+	}
+	sum, r := SumOrBig(terms)
+	if r == nil {
+		if z, err := sum.TryDivInt64(2); err == nil {
+			return z, nil
+		}
+		r = sum.BigRat()
+	}
+	return FromBigRat(r.Quo(r, big.NewRat(2, 1)))
+}
+
+// polygonAreaBig is like PolygonArea but computes entirely with big.Rat.
+func polygonAreaBig(vertices [][2]N) *big.Rat {
+	sum, p, q := new(big.Rat), new(big.Rat), new(big.Rat)
+	for i, a := range vertices {
+		b := vertices[(i+1)%len(vertices)]
+		p.Mul(a[0].BigRat(), b[1].BigRat())
+		q.Mul(a[1].BigRat(), b[0].BigRat())
+		sum.Add(sum, p.Sub(p, q))
+	}
+	return sum.Quo(sum, big.NewRat(2, 1))
+}
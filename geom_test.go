@@ -0,0 +1,168 @@
+package rat128_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/kbolino/rat128"
+)
+
+func TestOrient2D(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		A, B, C [2]rat128.N
+		Sign    int
+		Err     error
+	}{
+		{[2]rat128.N{Zero, Zero}, [2]rat128.N{New(1, 1), Zero}, [2]rat128.N{Zero, New(1, 1)}, 1, nil},
+		{[2]rat128.N{Zero, Zero}, [2]rat128.N{Zero, New(1, 1)}, [2]rat128.N{New(1, 1), Zero}, -1, nil},
+		{[2]rat128.N{Zero, Zero}, [2]rat128.N{New(1, 3), New(1, 7)}, [2]rat128.N{New(2, 3), New(2, 7)}, 0, nil},
+		{[2]rat128.N{New(1, 3), New(1, 3)}, [2]rat128.N{New(2, 3), New(2, 3)}, [2]rat128.N{New(1, 1), New(1, 1)}, 0, nil},
+		{[2]rat128.N{Zero, Zero}, [2]rat128.N{New(1, P1), New(1, P2)}, [2]rat128.N{New(1, P2), New(1, P1)}, 1, nil},
+		{[2]rat128.N{Zero, Zero}, [2]rat128.N{New(M, 1), Zero}, [2]rat128.N{New(M, 1), New(1, M)}, 1, nil},
+		{[2]rat128.N{Zero, Zero}, [2]rat128.N{New(M, 1), Zero}, [2]rat128.N{Zero, New(M, 1)}, 0, rat128.ErrNumOverflow},
+		{[2]rat128.N{New(-M, 1), Zero}, [2]rat128.N{New(M, 1), Zero}, [2]rat128.N{Zero, New(1, 1)}, 0, rat128.ErrNumOverflow},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%v,%v,%v", c.A, c.B, c.C), func(t *testing.T) {
+			sign, err := rat128.Orient2D(c.A[0], c.A[1], c.B[0], c.B[1], c.C[0], c.C[1])
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if sign != c.Sign {
+				t.Errorf("got %d, want %d", sign, c.Sign)
+			}
+		})
+	}
+}
+
+func TestN_CircleParam(t *testing.T) {
+	cases := []struct {
+		T, X, Y rat128.N
+		Err     error
+	}{
+		{New(0, 1), New(1, 1), New(0, 1), nil},
+		{New(1, 1), New(0, 1), New(1, 1), nil},
+		{New(-1, 1), New(0, 1), New(-1, 1), nil},
+		{New(1, 2), New(3, 5), New(4, 5), nil},
+		{New(2, 3), New(5, 13), New(12, 13), nil},
+		{New(2, 1), New(-3, 5), New(4, 5), nil},
+		{New(1<<31, 1), New(1-1<<62, 1+1<<62), New(1<<32, 1+1<<62), nil},
+		{New(1<<32, 1), Zero, Zero, rat128.ErrNumOverflow},
+	}
+	for _, c := range cases {
+		t.Run(c.T.String(), func(t *testing.T) {
+			x, y, err := c.T.CircleParam()
+			if err != c.Err {
+				t.Fatalf("got error %v, want %v", err, c.Err)
+			} else if err != nil {
+				return
+			}
+			if x != c.X || y != c.Y {
+				t.Errorf("got (%v, %v), want (%v, %v)", x, y, c.X, c.Y)
+			}
+			// x² + y² == 1, checked with big.Rat since the squares may overflow
+			x2, y2 := x.BigRat(), y.BigRat()
+			x2.Mul(x2, x2)
+			y2.Mul(y2, y2)
+			if s := x2.Add(x2, y2); s.Cmp(big.NewRat(1, 1)) != 0 {
+				t.Errorf("(%v, %v) is not on the unit circle", x, y)
+			}
+		})
+	}
+}
+
+func TestN_CircularDistance(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		X, Y, C rat128.N
+		D       rat128.N
+		Err     error
+	}{
+		{New(0, 1), New(1, 4), New(1, 1), New(1, 4), nil},
+		{New(1, 4), New(0, 1), New(1, 1), New(-1, 4), nil},
+		{New(1, 10), New(9, 10), New(1, 1), New(-1, 5), nil},
+		{New(9, 10), New(1, 10), New(1, 1), New(1, 5), nil},
+		{New(0, 1), New(1, 2), New(1, 1), New(1, 2), nil},
+		{New(1, 2), New(0, 1), New(1, 1), New(1, 2), nil},
+		{New(-3, 1), New(13, 2), New(4, 1), New(3, 2), nil},
+		{New(5, 1), New(5, 1), New(3, 1), New(0, 1), nil},
+		{New(1, 3), New(-M, 1), New(2, 3), New(0, 1), nil},
+		{New(0, 1), New(-M, 1), New(2, 3), New(1, 3), nil},
+		{New(0, 1), New(1, 1), New(0, 1), Zero, rat128.ErrInvalid},
+		{New(0, 1), New(1, 1), New(-1, 1), Zero, rat128.ErrInvalid},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s),(%s)@(%s)", c.X.RationalString("_"), c.Y.RationalString("_"), c.C.RationalString("_")), func(t *testing.T) {
+			d, err := c.X.CircularDistance(c.Y, c.C)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if d != c.D {
+				t.Errorf("got %v, want %v", d, c.D)
+			}
+		})
+	}
+}
+
+func TestCentroid(t *testing.T) {
+	const M = math.MaxInt64
+	pt := func(x, y rat128.N) [2]rat128.N { return [2]rat128.N{x, y} }
+	cases := []struct {
+		Name   string
+		Points [][2]rat128.N
+		C      [2]rat128.N
+		Err    error
+	}{
+		{"Single", [][2]rat128.N{pt(New(1, 2), New(-3, 4))}, pt(New(1, 2), New(-3, 4)), nil},
+		{"Triangle", [][2]rat128.N{pt(Zero, Zero), pt(New(1, 1), Zero), pt(Zero, New(1, 1))}, pt(New(1, 3), New(1, 3)), nil},
+		{"Square", [][2]rat128.N{pt(Zero, Zero), pt(New(2, 1), Zero), pt(New(2, 1), New(2, 1)), pt(Zero, New(2, 1))}, pt(New(1, 1), New(1, 1)), nil},
+		{"Fractions", [][2]rat128.N{pt(New(1, 2), New(1, 3)), pt(New(1, 3), New(1, 5))}, pt(New(5, 12), New(4, 15)), nil},
+		{"LargeSum", [][2]rat128.N{pt(New(M, 1), New(-M, 1)), pt(New(M-2, 1), New(-M, 1))}, pt(New(M-1, 1), New(-M, 1)), nil},
+		{"Overflow", [][2]rat128.N{pt(New(1, M), Zero), pt(New(1, M-1), Zero)}, [2]rat128.N{}, rat128.ErrNumOverflow},
+		{"Empty", nil, [2]rat128.N{}, rat128.ErrDivByZero},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			z, err := rat128.Centroid(c.Points)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if z != c.C {
+				t.Errorf("got %v, want %v", z, c.C)
+			}
+		})
+	}
+}
+
+func TestPolygonArea(t *testing.T) {
+	const M = math.MaxInt64
+	pt := func(x, y int64) [2]rat128.N { return [2]rat128.N{New(x, 1), New(y, 1)} }
+	cases := []struct {
+		Name     string
+		Vertices [][2]rat128.N
+		Area     rat128.N
+		Err      error
+	}{
+		{"Empty", nil, Zero, nil},
+		{"Segment", [][2]rat128.N{pt(0, 0), pt(3, 4)}, Zero, nil},
+		{"UnitSquare", [][2]rat128.N{pt(0, 0), pt(1, 0), pt(1, 1), pt(0, 1)}, New(1, 1), nil},
+		{"Clockwise", [][2]rat128.N{pt(0, 0), pt(0, 1), pt(1, 1), pt(1, 0)}, New(-1, 1), nil},
+		{"Centered", [][2]rat128.N{pt(-1, -1), pt(1, -1), pt(1, 1), pt(-1, 1)}, New(4, 1), nil},
+		{"Fractions", [][2]rat128.N{pt(0, 0), {New(1, 3), Zero}, {New(1, 3), New(1, 5)}}, New(1, 30), nil},
+		{"Bowtie", [][2]rat128.N{pt(0, 0), pt(2, 0), pt(0, 2), pt(2, 2)}, Zero, nil},
+		{"LargeSum", [][2]rat128.N{pt(0, 0), pt(M, 0), pt(M, 1), pt(0, 1)}, New(M, 1), nil},
+		{"LargeProduct", [][2]rat128.N{pt(0, 0), pt(M, 0), pt(0, 2)}, New(M, 1), nil},
+		{"NumOverflow", [][2]rat128.N{pt(0, 0), pt(M, 0), pt(M, M), pt(0, M)}, Zero, rat128.ErrNumOverflow},
+		{"DenOverflow", [][2]rat128.N{{New(1, M), Zero}, {Zero, New(1, M-1)}, pt(0, 0)}, Zero, rat128.ErrDenOverflow},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			z, err := rat128.PolygonArea(c.Vertices)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if z != c.Area {
+				t.Errorf("got %v, want %v", z, c.Area)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package rat128
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"math/bits"
+)
+
+// OrderedBytes encodes x as 24 bytes such that comparing the encodings of two
+// values with bytes.Compare gives the same result as comparing the values
+// with Cmp. This makes the encoding suitable as a key in ordered stores.
+//
+// The first 8 bytes are floor(x) in offset binary, and the remaining 16 bytes
+// are the fractional part of x, truncated to a 128-bit binary fraction.
+// Since distinct values differ by more than 2^-126, the truncation never
+// makes distinct values collide, but the encoding is not designed to be
+// decoded; use SortableKey for that. OrderedBytes returns an error only if x
+// is not valid, as reported by CheckInvariants.
+func (x N) OrderedBytes() ([]byte, error) {
+	if err := CheckInvariants(x); err != nil {
+		return nil, err
+	}
+	return x.appendOrdered(make([]byte, 0, 24)), nil
+}
+
+// appendOrdered appends the encoding described by OrderedBytes to b.
+func (x N) appendOrdered(b []byte) []byte {
+	m, n := x.Num(), x.Den()
+	q, r := m/n, m%n
+	if r < 0 {
+		q, r = q-1, r+n
+	}
+	// r/n*2^128 is found by long division in base 2^64; r < n, so neither
+	// step panics
+	f1, rem := bits.Div64(uint64(r), 0, uint64(n))
+	f0, _ := bits.Div64(rem, 0, uint64(n))
+	b = binary.BigEndian.AppendUint64(b, uint64(q)^1<<63)
+	b = binary.BigEndian.AppendUint64(b, f1)
+	return binary.BigEndian.AppendUint64(b, f0)
+}
+
+// SortableKey encodes x as 32 bytes such that comparing the encodings of two
+// values with bytes.Compare gives the same result as comparing the values
+// with Cmp, and FromSortableKey recovers x from its encoding.
+//
+// The first 24 bytes are the same as OrderedBytes, which already determine
+// the order, and the last 8 bytes are the denominator in big-endian order,
+// which makes it possible to recover the numerator exactly.
+func (x N) SortableKey() []byte {
+	b := x.appendOrdered(make([]byte, 0, 32))
+	return binary.BigEndian.AppendUint64(b, uint64(x.Den()))
+}
+
+// FromSortableKey decodes a value encoded by SortableKey.
+// FromSortableKey returns ErrFmtInvalid if key is not the right length or is
+// otherwise not a possible output of SortableKey, and ErrNotReduced if the
+// stored denominator is not that of a reduced fraction.
+func FromSortableKey(key []byte) (N, error) {
+	if len(key) != 32 {
+		return N{}, ErrFmtInvalid
+	}
+	q := int64(binary.BigEndian.Uint64(key) ^ 1<<63)
+	f1 := binary.BigEndian.Uint64(key[8:])
+	f0 := binary.BigEndian.Uint64(key[16:])
+	n := binary.BigEndian.Uint64(key[24:])
+	if n == 0 || n > math.MaxInt64 {
+		return N{}, ErrFmtInvalid
+	}
+	// the fraction f = f1:f0 is floor(r*2^128/n), so r is the ceiling of
+	// f*n/2^128, which is the top word of the 192-bit product f*n plus one if
+	// any of the lower bits are set
+	h0, l0 := bits.Mul64(f0, n)
+	h1, l1 := bits.Mul64(f1, n)
+	mid, c := bits.Add64(l1, h0, 0)
+	r := h1 + c
+	if mid != 0 || l0 != 0 {
+		r++
+	}
+	if r >= n {
+		return N{}, ErrFmtInvalid
+	}
+	m, ok := addMul64(int64(r), q, int64(n))
+	if !ok || m == math.MinInt64 {
+		return N{}, ErrFmtInvalid
+	}
+	x, err := Try(m, int64(n))
+	if err != nil {
+		return N{}, ErrFmtInvalid
+	}
+	if uint64(x.Den()) != n {
+		return N{}, ErrNotReduced
+	}
+	if !bytes.Equal(x.SortableKey(), key) {
+		return N{}, ErrFmtInvalid
+	}
+	return x, nil
+}
+
+// Pack returns x as two 64-bit words, for example to be stored with
+// atomic.Uint64 operations or compared-and-swapped as a unit. The first word
+// is the numerator in offset binary, so that among values with the same
+// denominator the words order like the values, and the second word is the
+// denominator minus one, so that Pack(0) is [1<<63, 0].
+// Unpack recovers x from the words.
+func (x N) Pack() [2]uint64 {
+	return [2]uint64{uint64(x.m) ^ 1<<63, uint64(x.n)}
+}
+
+// Unpack recovers a value packed by Pack.
+// Unpack returns the same errors as CheckInvariants if p does not hold a
+// valid value.
+func Unpack(p [2]uint64) (N, error) {
+	x := N{int64(p[0] ^ 1<<63), int64(p[1])}
+	if !x.IsValid() {
+		return N{}, CheckInvariants(x)
+	}
+	return x, nil
+}
+
+// ToInt32Pair returns the numerator and denominator of x as int32 values, for
+// example to be uploaded to hardware that only has 32-bit integers, and true.
+// If either does not fit in an int32, ToInt32Pair returns 0, 0, and false.
+func (x N) ToInt32Pair() (num, den int32, ok bool) {
+	m, d := x.Num(), x.Den()
+	if m < math.MinInt32 || m > math.MaxInt32 || d > math.MaxInt32 {
+		return 0, 0, false
+	}
+	return int32(m), int32(d), true
+}
+
+// FromInt32Pair returns num/den in lowest terms, the inverse of ToInt32Pair.
+// FromInt32Pair returns ErrDenInvalid if den is not positive.
+func FromInt32Pair(num, den int32) (N, error) {
+	return Try(int64(num), int64(den))
+}
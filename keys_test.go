@@ -0,0 +1,218 @@
+package rat128_test
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/kbolino/rat128"
+)
+
+// keyOrderCases are sorted in ascending order, with adjacent values chosen to
+// be as close together as possible.
+var keyOrderCases = []rat128.N{
+	New(-math.MaxInt64, 1),
+	New(-math.MaxInt64+1, 1),
+	New(-3, 1),
+	New(-5, 2),
+	New(-1, 1),
+	New(-(math.MaxInt64 - 1), math.MaxInt64),
+	New(-1, 2),
+	New(-1, math.MaxInt64-1),
+	New(-1, math.MaxInt64),
+	New(0, 1),
+	New(1, math.MaxInt64),
+	New(1, math.MaxInt64-1),
+	New(math.MaxInt64/3, math.MaxInt64),
+	New(1, 3),
+	New(math.MaxInt64/3+1, math.MaxInt64),
+	New(1, 2),
+	New(math.MaxInt64-2, math.MaxInt64-1),
+	New(math.MaxInt64-1, math.MaxInt64),
+	New(1, 1),
+	New(math.MaxInt64, math.MaxInt64-1),
+	New(2, 1),
+	New(math.MaxInt64, 1),
+}
+
+func TestN_OrderedBytes(t *testing.T) {
+	var prev []byte
+	for i, x := range keyOrderCases {
+		b, err := x.OrderedBytes()
+		if err != nil {
+			t.Fatalf("%v: got unexpected error %v", x, err)
+		} else if len(b) != 24 {
+			t.Errorf("%v: got %d bytes, want 24", x, len(b))
+		}
+		if i > 0 && bytes.Compare(prev, b) >= 0 {
+			t.Errorf("%v: key %x is not greater than key %x of %v", x, b, prev, keyOrderCases[i-1])
+		}
+		prev = b
+	}
+}
+
+func TestN_OrderedBytesRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	xs := make([]rat128.N, 1000)
+	keys := make(map[rat128.N][]byte, len(xs))
+	for i := range xs {
+		xs[i] = rat128.RandN(r)
+		keys[xs[i]], _ = xs[i].OrderedBytes()
+	}
+	sort.Slice(xs, func(i, j int) bool { return xs[i].Cmp(xs[j]) < 0 })
+	for i := 1; i < len(xs); i++ {
+		if c := bytes.Compare(keys[xs[i-1]], keys[xs[i]]); c != xs[i-1].Cmp(xs[i]) {
+			t.Errorf("%v vs %v: key comparison is %d", xs[i-1], xs[i], c)
+		}
+	}
+}
+
+func TestN_SortableKey(t *testing.T) {
+	var prev []byte
+	for i, x := range keyOrderCases {
+		b := x.SortableKey()
+		if len(b) != 32 {
+			t.Errorf("%v: got %d bytes, want 32", x, len(b))
+		}
+		if i > 0 && bytes.Compare(prev, b) >= 0 {
+			t.Errorf("%v: key %x is not greater than key %x of %v", x, b, prev, keyOrderCases[i-1])
+		}
+		prev = b
+		if y, err := rat128.FromSortableKey(b); err != nil {
+			t.Errorf("%v: got unexpected error %v", x, err)
+		} else if y != x {
+			t.Errorf("%v: got %v after round trip", x, y)
+		}
+	}
+}
+
+func TestSortableKeyRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x := rat128.RandN(r)
+		if y, err := rat128.FromSortableKey(x.SortableKey()); err != nil {
+			t.Fatalf("%v: got unexpected error %v", x, err)
+		} else if y != x {
+			t.Fatalf("%v: got %v after round trip", x, y)
+		}
+	}
+}
+
+func TestFromSortableKey(t *testing.T) {
+	valid := New(1, 3).SortableKey()
+	corrupt := func(i int, b byte) []byte {
+		key := append([]byte(nil), valid...)
+		key[i] = b
+		return key
+	}
+	cases := []struct {
+		Name string
+		Key  []byte
+		Err  error
+	}{
+		{"Empty", nil, rat128.ErrFmtInvalid},
+		{"Short", valid[:24], rat128.ErrFmtInvalid},
+		{"Long", append(append([]byte(nil), valid...), 0), rat128.ErrFmtInvalid},
+		{"ZeroDen", corrupt(31, 0), rat128.ErrFmtInvalid},
+		{"Fraction", corrupt(23, 0x56), rat128.ErrFmtInvalid},
+		{"NotReduced", corrupt(31, 6), rat128.ErrNotReduced},
+		{"MinInt64", append(make([]byte, 24), 0, 0, 0, 0, 0, 0, 0, 1), rat128.ErrFmtInvalid},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			if x, err := rat128.FromSortableKey(c.Key); err != c.Err {
+				t.Errorf("got (%v, %v), want error %v", x, err, c.Err)
+			}
+		})
+	}
+}
+
+func TestPackUnpack(t *testing.T) {
+	for _, x := range keyOrderCases {
+		p := x.Pack()
+		if y, err := rat128.Unpack(p); err != nil {
+			t.Errorf("%v: got unexpected error %v", x, err)
+		} else if y != x {
+			t.Errorf("%v: got %v after round trip", x, y)
+		}
+	}
+	if p := Zero.Pack(); p != [2]uint64{1 << 63, 0} {
+		t.Errorf("got %x for zero, want [8000000000000000 0]", p)
+	}
+	if a, b := New(-3, 7).Pack(), New(2, 7).Pack(); a[0] >= b[0] {
+		t.Errorf("numerator word of -3/7 (%x) is not less than that of 2/7 (%x)", a[0], b[0])
+	}
+	cases := []struct {
+		Name string
+		P    [2]uint64
+		Err  error
+	}{
+		{"ZeroWords", [2]uint64{0, 0}, rat128.ErrNumOverflow},
+		{"NegativeDen", [2]uint64{1<<63 + 1, 1 << 63}, rat128.ErrDenInvalid},
+		{"MaxDen", [2]uint64{1<<63 + 1, math.MaxInt64}, rat128.ErrDenInvalid},
+		{"NotReduced", [2]uint64{1<<63 + 2, 3}, rat128.ErrNotReduced},
+		{"ZeroNotReduced", [2]uint64{1 << 63, 1}, rat128.ErrNotReduced},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			if x, err := rat128.Unpack(c.P); !errors.Is(err, c.Err) {
+				t.Errorf("got (%v, %v), want error %v", x, err, c.Err)
+			}
+		})
+	}
+}
+
+func TestN_ToInt32Pair(t *testing.T) {
+	cases := []struct {
+		X        rat128.N
+		Num, Den int32
+		OK       bool
+	}{
+		{Zero, 0, 1, true},
+		{New(-3, 7), -3, 7, true},
+		{New(math.MaxInt32, 1), math.MaxInt32, 1, true},
+		{New(math.MinInt32, 1), math.MinInt32, 1, true},
+		{New(1, math.MaxInt32), 1, math.MaxInt32, true},
+		{New(math.MaxInt32+1, 1), 0, 0, false},
+		{New(math.MinInt32-1, 1), 0, 0, false},
+		{New(1, math.MaxInt32+1), 0, 0, false},
+		{New(math.MaxInt64, math.MaxInt64-1), 0, 0, false},
+	}
+	for _, c := range cases {
+		t.Run(c.X.RationalString("_"), func(t *testing.T) {
+			num, den, ok := c.X.ToInt32Pair()
+			if num != c.Num || den != c.Den || ok != c.OK {
+				t.Errorf("got (%d, %d, %t), want (%d, %d, %t)", num, den, ok, c.Num, c.Den, c.OK)
+			}
+			if !ok {
+				return
+			}
+			if y, err := rat128.FromInt32Pair(num, den); err != nil || y != c.X {
+				t.Errorf("got (%v, %v) after round trip", y, err)
+			}
+		})
+	}
+}
+
+func TestFromInt32Pair(t *testing.T) {
+	cases := []struct {
+		Num, Den int32
+		X        rat128.N
+		Err      error
+	}{
+		{6, 4, New(3, 2), nil},
+		{-6, 4, New(-3, 2), nil},
+		{0, 5, Zero, nil},
+		{math.MinInt32, 2, New(math.MinInt32/2, 1), nil},
+		{1, 0, Zero, rat128.ErrDenInvalid},
+		{1, -2, Zero, rat128.ErrDenInvalid},
+	}
+	for _, c := range cases {
+		if x, err := rat128.FromInt32Pair(c.Num, c.Den); err != c.Err || x != c.X {
+			t.Errorf("%d/%d: got (%v, %v), want (%v, %v)", c.Num, c.Den, x, err, c.X, c.Err)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package rat128
+
+import "fmt"
+
+// MatMul returns the matrix product of a and b, which are given as slices of
+// rows. Each element of the result is computed with Dot, so intermediate
+// overflow is tolerated as long as the element itself fits.
+// MatMul returns ErrInvalid if either matrix is empty or not rectangular, or
+// if the number of columns of a does not equal the number of rows of b.
+// If an element overflows, the error gives its row and column.
+func MatMul(a, b [][]N) ([][]N, error) {
+	rows, inner, err := matDims(a)
+	if err != nil {
+		return nil, fmt.Errorf("left operand: %w", err)
+	}
+	bRows, cols, err := matDims(b)
+	if err != nil {
+		return nil, fmt.Errorf("right operand: %w", err)
+	}
+	if inner != bRows {
+		return nil, fmt.Errorf("%w: cannot multiply %dx%d by %dx%d", ErrInvalid, rows, inner, bRows, cols)
+	}
+	out := make([][]N, rows)
+	col := make([]N, inner)
+	for i := range out {
+		out[i] = make([]N, cols)
+	}
+	for j := 0; j < cols; j++ {
+		for k := range col {
+			col[k] = b[k][j]
+		}
+		for i := range out {
+			out[i][j], err = Dot(a[i], col)
+			if err != nil {
+				return nil, fmt.Errorf("computing element (%d, %d): %w", i, j, err)
+			}
+		}
+	}
+	return out, nil
+}
+
+// matDims returns the number of rows and columns of a, or ErrInvalid if a is
+// empty or not rectangular.
+func matDims(a [][]N) (rows, cols int, err error) {
+	if len(a) == 0 || len(a[0]) == 0 {
+		return 0, 0, fmt.Errorf("%w: empty matrix", ErrInvalid)
+	}
+	rows, cols = len(a), len(a[0])
+	for i, row := range a[1:] {
+		if len(row) != cols {
+			return 0, 0, fmt.Errorf("%w: row %d has %d columns, want %d", ErrInvalid, i+1, len(row), cols)
+		}
+	}
+	return rows, cols, nil
+}
+
+// RREF returns the reduced row echelon form of a, computed exactly by
+// Gauss-Jordan elimination. The input matrix is not modified.
+// When choosing a pivot for a column, RREF picks the simplest nonzero
+// candidate (smallest denominator, then smallest magnitude) to limit the
+// growth of the entries.
+// RREF returns ErrInvalid if a is empty or not rectangular. If an entry
+// overflows, the error gives the column being eliminated.
+func RREF(a [][]N) ([][]N, error) {
+	rows, cols, err := matDims(a)
+	if err != nil {
+		return nil, err
+	}
+	out := make([][]N, rows)
+	for i, row := range a {
+		out[i] = append([]N(nil), row...)
+	}
+	r := 0
+	for c := 0; c < cols && r < rows; c++ {
+		p := -1
+		for i := r; i < rows; i++ {
+			if !out[i][c].IsZero() && (p < 0 || simpler(out[i][c], out[p][c])) {
+				p = i
+			}
+		}
+		if p < 0 {
+			continue
+		}
+		out[r], out[p] = out[p], out[r]
+		if err := rrefStep(out, r, c); err != nil {
+			return nil, fmt.Errorf("eliminating column %d: %w", c, err)
+		}
+		r++
+	}
+	return out, nil
+}
+
+// rrefStep scales row r of a so that a[r][c] is 1, then subtracts multiples
+// of it from every other row so that the rest of column c is zero.
+// The entries in columns before c are already zero in row r.
+func rrefStep(a [][]N, r, c int) error {
+	pivotRow := a[r]
+	inv, err := pivotRow[c].TryInv()
+	if err != nil {
+		return err
+	}
+	for j := c; j < len(pivotRow); j++ {
+		if pivotRow[j], err = pivotRow[j].TryMul(inv); err != nil {
+			return err
+		}
+	}
+	for i, row := range a {
+		f := row[c]
+		if i == r || f.IsZero() {
+			continue
+		}
+		for j := c; j < len(row); j++ {
+			d, err := f.TryMul(pivotRow[j])
+			if err != nil {
+				return err
+			}
+			if row[j], err = row[j].TrySub(d); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Det returns the determinant of the square matrix a, computed by Bareiss
+// elimination, which divides out the previous pivot at each step to limit
+// the growth of intermediate values. The input matrix is not modified.
+// Det returns ErrInvalid if a is empty or not square. If an intermediate
+// value overflows, the error gives the elimination step.
+func Det(a [][]N) (N, error) {
+	rows, cols, err := matDims(a)
+	if err != nil {
+		return N{}, err
+	}
+	if rows != cols {
+		return N{}, fmt.Errorf("%w: matrix is %dx%d, not square", ErrInvalid, rows, cols)
+	}
+	m := make([][]N, rows)
+	for i, row := range a {
+		m[i] = append([]N(nil), row...)
+	}
+	neg := false
+	prev := N{1, 0}
+	for k := 0; k < rows-1; k++ {
+		if m[k][k].IsZero() {
+			p := k + 1
+			for p < rows && m[p][k].IsZero() {
+				p++
+			}
+			if p == rows {
+				return N{}, nil
+			}
+			m[k], m[p] = m[p], m[k]
+			neg = !neg
+		}
+		for i := k + 1; i < rows; i++ {
+			for j := k + 1; j < rows; j++ {
+				if m[i][j], err = bareiss(m[i][j], m[k][k], m[i][k], m[k][j], prev); err != nil {
+					return N{}, fmt.Errorf("elimination step %d: %w", k, err)
+				}
+			}
+		}
+		prev = m[k][k]
+	}
+	det := m[rows-1][rows-1]
+	if neg {
+		det = det.Neg()
+	}
+	return det, nil
+}
+
+// bareiss returns (a*b - c*d) / e.
+func bareiss(a, b, c, d, e N) (N, error) {
+	x, err := a.TryMul(b)
+	if err != nil {
+		return N{}, err
+	}
+	y, err := c.TryMul(d)
+	if err != nil {
+		return N{}, err
+	}
+	if x, err = x.TrySub(y); err != nil {
+		return N{}, err
+	}
+	return x.TryDiv(e)
+}
@@ -0,0 +1,189 @@
+package rat128_test
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/kbolino/rat128"
+)
+
+func TestMatMul(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		Name string
+		A, B [][]rat128.N
+		C    [][]rat128.N
+		Err  error
+	}{
+		{
+			"2x2",
+			[][]rat128.N{{New(1, 2), New(1, 3)}, {New(0, 1), New(1, 1)}},
+			[][]rat128.N{{New(2, 1), New(0, 1)}, {New(3, 1), New(1, 4)}},
+			[][]rat128.N{{New(2, 1), New(1, 12)}, {New(3, 1), New(1, 4)}},
+			nil,
+		},
+		{
+			"2x3*3x1",
+			[][]rat128.N{{New(1, 1), New(2, 1), New(3, 1)}, {New(-1, 2), New(1, 3), New(-1, 6)}},
+			[][]rat128.N{{New(1, 1)}, {New(1, 1)}, {New(1, 1)}},
+			[][]rat128.N{{New(6, 1)}, {New(-1, 3)}},
+			nil,
+		},
+		{
+			"IntermediateOverflow",
+			[][]rat128.N{{New(M, 1), New(M, 1)}},
+			[][]rat128.N{{New(3, 1)}, {New(-3, 1)}},
+			[][]rat128.N{{Zero}},
+			nil,
+		},
+		{
+			"Overflow",
+			[][]rat128.N{{New(1, 1)}, {New(M, 1)}},
+			[][]rat128.N{{New(2, 1)}},
+			nil,
+			rat128.ErrNumOverflow,
+		},
+		{
+			"Mismatch",
+			[][]rat128.N{{New(1, 1), New(1, 1)}},
+			[][]rat128.N{{New(1, 1), New(1, 1)}},
+			nil,
+			rat128.ErrInvalid,
+		},
+		{
+			"Ragged",
+			[][]rat128.N{{New(1, 1), New(1, 1)}, {New(1, 1)}},
+			[][]rat128.N{{New(1, 1)}, {New(1, 1)}},
+			nil,
+			rat128.ErrInvalid,
+		},
+		{"Empty", nil, [][]rat128.N{{New(1, 1)}}, nil, rat128.ErrInvalid},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			m, err := rat128.MatMul(c.A, c.B)
+			if !errors.Is(err, c.Err) {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if c.Err == nil && !reflect.DeepEqual(m, c.C) {
+				t.Errorf("got %v, want %v", m, c.C)
+			}
+		})
+	}
+}
+
+func TestRREF(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		Name string
+		A, R [][]rat128.N
+		Err  error
+	}{
+		{
+			"Identity",
+			[][]rat128.N{{New(2, 1), New(1, 1)}, {New(1, 1), New(3, 1)}},
+			[][]rat128.N{{New(1, 1), Zero}, {Zero, New(1, 1)}},
+			nil,
+		},
+		{
+			"Augmented",
+			// x + y/2 = 1, x/3 - y = 2  =>  x = 12/7, y = -10/7
+			[][]rat128.N{{New(1, 1), New(1, 2), New(1, 1)}, {New(1, 3), New(-1, 1), New(2, 1)}},
+			[][]rat128.N{{New(1, 1), Zero, New(12, 7)}, {Zero, New(1, 1), New(-10, 7)}},
+			nil,
+		},
+		{
+			"Singular",
+			[][]rat128.N{{New(1, 2), New(1, 1), New(3, 1)}, {New(1, 1), New(2, 1), New(6, 1)}, {Zero, Zero, New(1, 1)}},
+			[][]rat128.N{{New(1, 1), New(2, 1), Zero}, {Zero, Zero, New(1, 1)}, {Zero, Zero, Zero}},
+			nil,
+		},
+		{
+			"ZeroColumn",
+			[][]rat128.N{{Zero, New(3, 1)}, {Zero, New(1, 2)}},
+			[][]rat128.N{{Zero, New(1, 1)}, {Zero, Zero}},
+			nil,
+		},
+		{
+			"Overflow",
+			[][]rat128.N{{New(1, 1), New(M, 1)}, {New(1, 1), New(-M, 1)}},
+			nil,
+			rat128.ErrNumOverflow,
+		},
+		{"Ragged", [][]rat128.N{{New(1, 1)}, {}}, nil, rat128.ErrInvalid},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			a := make([][]rat128.N, len(c.A))
+			for i := range a {
+				a[i] = make([]rat128.N, len(c.A[i]))
+				copy(a[i], c.A[i])
+			}
+			r, err := rat128.RREF(a)
+			if !errors.Is(err, c.Err) {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if c.Err == nil && !reflect.DeepEqual(r, c.R) {
+				t.Errorf("got %v, want %v", r, c.R)
+			}
+			if !reflect.DeepEqual(a, c.A) {
+				t.Errorf("input modified: got %v, want %v", a, c.A)
+			}
+		})
+	}
+}
+
+func TestDet(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		Name string
+		A    [][]rat128.N
+		Det  rat128.N
+		Err  error
+	}{
+		{"1x1", [][]rat128.N{{New(-3, 4)}}, New(-3, 4), nil},
+		{"2x2", [][]rat128.N{{New(1, 2), New(1, 3)}, {New(1, 4), New(1, 5)}}, New(1, 60), nil},
+		{
+			"3x3",
+			[][]rat128.N{
+				{New(2, 1), New(-1, 1), Zero},
+				{New(-1, 1), New(2, 1), New(-1, 1)},
+				{Zero, New(-1, 1), New(2, 1)},
+			},
+			New(4, 1),
+			nil,
+		},
+		{
+			"Swap",
+			[][]rat128.N{
+				{Zero, New(1, 1), New(2, 1)},
+				{New(1, 2), Zero, New(1, 1)},
+				{New(1, 1), New(1, 1), Zero},
+			},
+			New(2, 1),
+			nil,
+		},
+		{
+			"Singular",
+			[][]rat128.N{
+				{Zero, New(1, 1), New(2, 1)},
+				{Zero, New(3, 1), New(1, 1)},
+				{Zero, New(1, 7), New(1, 1)},
+			},
+			Zero,
+			nil,
+		},
+		{"Overflow", [][]rat128.N{{New(M, 1), New(1, 1)}, {New(-1, 1), New(M, 1)}}, Zero, rat128.ErrNumOverflow},
+		{"NotSquare", [][]rat128.N{{New(1, 1), New(1, 1)}}, Zero, rat128.ErrInvalid},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			det, err := rat128.Det(c.A)
+			if !errors.Is(err, c.Err) {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if c.Err == nil && det != c.Det {
+				t.Errorf("got %v, want %v", det, c.Det)
+			}
+		})
+	}
+}
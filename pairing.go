@@ -0,0 +1,83 @@
+package rat128
+
+import (
+	"math/big"
+)
+
+// PairingEncode encodes x as a single non-negative integer.
+//
+// The numerator is first mapped to a non-negative integer a by interleaving
+// its positive and negative values (0, -1, 1, -2, 2, ... map to 0, 1, 2, 3,
+// 4, ...), and the denominator is mapped to b = Den()-1. Then the Cantor
+// pairing function combines a and b into (a+b)*(a+b+1)/2 + b.
+//
+// Distinct valid values always have distinct encodings, and PairingDecode
+// recovers x from its encoding. PairingEncode returns an error only if x is
+// not valid, as reported by CheckInvariants.
+func (x N) PairingEncode() (*big.Int, error) {
+	if err := CheckInvariants(x); err != nil {
+		return nil, err
+	}
+	a := big.NewInt(x.Num())
+	if a.Sign() < 0 {
+		// -m maps to 2*m-1
+		a.Neg(a)
+		a.Lsh(a, 1)
+		a.Sub(a, big.NewInt(1))
+	} else {
+		// m maps to 2*m
+		a.Lsh(a, 1)
+	}
+	b := big.NewInt(x.n)
+	w := new(big.Int).Add(a, b)
+	z := new(big.Int).Add(w, big.NewInt(1))
+	z.Mul(z, w)
+	z.Rsh(z, 1)
+	return z.Add(z, b), nil
+}
+
+// PairingDecode decodes a value encoded by PairingEncode.
+// PairingDecode returns ErrFmtInvalid if z is negative, an overflow error if
+// the numerator or denominator of the decoded value doesn't fit, and
+// ErrNotReduced if they are not in lowest terms, since then z could not have
+// been produced by PairingEncode.
+func PairingDecode(z *big.Int) (N, error) {
+	if z.Sign() < 0 {
+		return N{}, ErrFmtInvalid
+	}
+	// w = floor((sqrt(8*z+1)-1)/2), t = w*(w+1)/2, b = z-t, a = w-b
+	w := new(big.Int).Lsh(z, 3)
+	w.Add(w, big.NewInt(1))
+	w.Sqrt(w)
+	w.Sub(w, big.NewInt(1))
+	w.Rsh(w, 1)
+	t := new(big.Int).Add(w, big.NewInt(1))
+	t.Mul(t, w)
+	t.Rsh(t, 1)
+	b := t.Sub(z, t)
+	a := w.Sub(w, b)
+	// undo the interleaving of the numerator
+	if a.Bit(0) != 0 {
+		a.Add(a, big.NewInt(1))
+		a.Rsh(a, 1)
+		a.Neg(a)
+	} else {
+		a.Rsh(a, 1)
+	}
+	if !a.IsInt64() {
+		return N{}, ErrNumOverflow
+	}
+	// b = Den()-1 must be less than math.MaxInt64
+	b.Add(b, big.NewInt(1))
+	if !b.IsInt64() {
+		return N{}, ErrDenOverflow
+	}
+	x, err := Try(a.Int64(), b.Int64())
+	if err != nil {
+		return N{}, err
+	}
+	if x.Num() != a.Int64() || x.Den() != b.Int64() {
+		return N{}, ErrNotReduced
+	}
+	return x, nil
+}
@@ -0,0 +1,88 @@
+package rat128_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/kbolino/rat128"
+)
+
+func TestN_PairingEncode(t *testing.T) {
+	cases := []struct {
+		Rat rat128.N
+		Z   int64
+	}{
+		{New(0, 1), 0},
+		{New(-1, 1), 1},
+		{New(1, 2), 7},
+		{New(1, 1), 3},
+		{New(-1, 2), 4},
+	}
+	for _, c := range cases {
+		t.Run(c.Rat.String(), func(t *testing.T) {
+			z, err := c.Rat.PairingEncode()
+			if err != nil {
+				t.Fatalf("got unexpected error %v", err)
+			}
+			if z.Cmp(big.NewInt(c.Z)) != 0 {
+				t.Errorf("got %v, want %d", z, c.Z)
+			}
+		})
+	}
+}
+
+func TestPairingDecode(t *testing.T) {
+	cases := []struct {
+		Z   int64
+		Rat rat128.N
+		Err error
+	}{
+		{-1, Zero, rat128.ErrFmtInvalid},
+		{0, New(0, 1), nil},
+		{1, New(-1, 1), nil},
+		{2, Zero, rat128.ErrNotReduced}, // 0/2
+		{3, New(1, 1), nil},
+		{4, New(-1, 2), nil},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprint(c.Z), func(t *testing.T) {
+			x, err := rat128.PairingDecode(big.NewInt(c.Z))
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if c.Err == nil && x != c.Rat {
+				t.Errorf("got %v, want %v", x, c.Rat)
+			}
+		})
+	}
+}
+
+func TestPairingRoundTrip(t *testing.T) {
+	xs := []rat128.N{
+		New(math.MaxInt64, 1),
+		New(-math.MaxInt64, 1),
+		New(1, math.MaxInt64),
+		New(-1, math.MaxInt64),
+		New(math.MaxInt64-1, math.MaxInt64),
+		New(-math.MaxInt64, math.MaxInt64-1),
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		xs = append(xs, rat128.RandN(r))
+	}
+	for _, x := range xs {
+		z, err := x.PairingEncode()
+		if err != nil {
+			t.Fatalf("(%v).PairingEncode(): got unexpected error %v", x, err)
+		}
+		y, err := rat128.PairingDecode(z)
+		if err != nil {
+			t.Fatalf("PairingDecode(%v): got unexpected error %v", z, err)
+		}
+		if y != x {
+			t.Errorf("PairingDecode(%v): got %v, want %v", z, y, x)
+		}
+	}
+}
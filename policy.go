@@ -0,0 +1,119 @@
+package rat128
+
+import (
+	"math"
+	"math/big"
+)
+
+// OverflowPolicy determines what the *With arithmetic methods, such as
+// AddWith, do when a result overflows.
+// The zero value is OverflowError.
+type OverflowPolicy int
+
+// Overflow policies supported by this package.
+const (
+	// OverflowError returns an error, like the Try* methods.
+	OverflowError OverflowPolicy = iota
+	// OverflowSaturate clamps a result whose magnitude is too large to
+	// ±math.MaxInt64. Other overflows, where the magnitude is in range but
+	// the denominator is too large, still return an error.
+	OverflowSaturate
+	// OverflowApprox returns the closest value whose numerator and
+	// denominator both fit, clamping to ±math.MaxInt64 if necessary.
+	// Thus, the only error it returns is ErrDivByZero.
+	OverflowApprox
+)
+
+// AddWith is like TryAdd but handles overflow according to policy.
+func (x N) AddWith(y N, policy OverflowPolicy) (N, error) {
+	z, err := x.TryAdd(y)
+	return withPolicy(z, err, policy, func() *big.Rat {
+		return new(big.Rat).Add(x.BigRat(), y.BigRat())
+	})
+}
+
+// SubWith is like TrySub but handles overflow according to policy.
+func (x N) SubWith(y N, policy OverflowPolicy) (N, error) {
+	return x.AddWith(y.Neg(), policy)
+}
+
+// MulWith is like TryMul but handles overflow according to policy.
+func (x N) MulWith(y N, policy OverflowPolicy) (N, error) {
+	z, err := x.TryMul(y)
+	return withPolicy(z, err, policy, func() *big.Rat {
+		return new(big.Rat).Mul(x.BigRat(), y.BigRat())
+	})
+}
+
+// DivWith is like TryDiv but handles overflow according to policy.
+// Division by zero always returns ErrDivByZero.
+func (x N) DivWith(y N, policy OverflowPolicy) (N, error) {
+	if y.IsZero() {
+		return N{}, ErrDivByZero
+	}
+	z, err := x.TryDiv(y)
+	return withPolicy(z, err, policy, func() *big.Rat {
+		return new(big.Rat).Quo(x.BigRat(), y.BigRat())
+	})
+}
+
+// withPolicy applies policy to the result z and error err of an operation
+// whose exact result is computed by exact if needed.
+func withPolicy(z N, err error, policy OverflowPolicy, exact func() *big.Rat) (N, error) {
+	if err == nil || policy == OverflowError {
+		return z, err
+	}
+	r := exact()
+	if x, ok := clampBigRat(r); ok {
+		return x, nil
+	}
+	if policy == OverflowSaturate {
+		return N{}, err
+	}
+	return approxBigRatFit(r)
+}
+
+// clampBigRat returns ±math.MaxInt64 and true if |r| > math.MaxInt64, or
+// else 0 and false.
+func clampBigRat(r *big.Rat) (N, bool) {
+	max := new(big.Rat).SetInt64(math.MaxInt64)
+	switch {
+	case r.Cmp(max) > 0:
+		return N{math.MaxInt64, 0}, true
+	case r.Cmp(max.Neg(max)) < 0:
+		return N{-math.MaxInt64, 0}, true
+	}
+	return N{}, false
+}
+
+// approxBigRatFit returns the best approximation of r whose numerator and
+// denominator both fit, for |r| <= math.MaxInt64.
+func approxBigRatFit(r *big.Rat) (N, error) {
+	if new(big.Rat).Abs(r).Cmp(big.NewRat(1, 1)) <= 0 {
+		return ApproxBigRat(r, math.MaxInt64)
+	}
+	// for |r| > 1, the numerator is the limiting factor, so approximate
+	// 1/r instead, which bounds the numerator of the result
+	inv, err := ApproxBigRat(new(big.Rat).Inv(r), math.MaxInt64)
+	if err != nil {
+		return N{}, err
+	}
+	return inv.TryInv()
+}
+
+// RemainingHeadroom returns how far x is below the largest value of N,
+// math.MaxInt64, and how far it is above the smallest, -math.MaxInt64, each
+// rounded up to an integer and capped at math.MaxInt64. This is a quick
+// check before accumulating into x: adding y to x certainly overflows if y is
+// more than up above zero or more than down below it. The converse does not
+// hold, since a sum can also overflow because its denominator or the
+// numerator over that denominator does not fit.
+func (x N) RemainingHeadroom() (up, down N) {
+	up, down = N{math.MaxInt64, 0}, N{math.MaxInt64, 0}
+	if x.m > 0 {
+		up.m -= x.Round(RoundFloor).m
+	} else if x.m < 0 {
+		down.m += x.Round(RoundCeiling).m
+	}
+	return up, down
+}
@@ -0,0 +1,121 @@
+package rat128_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/kbolino/rat128"
+)
+
+func TestN_AddWith(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		X, Y   rat128.N
+		Policy rat128.OverflowPolicy
+		Z      rat128.N
+		Err    error
+	}{
+		{New(1, 2), New(1, 3), rat128.OverflowError, New(5, 6), nil},
+		{New(1, 2), New(1, 3), rat128.OverflowSaturate, New(5, 6), nil},
+		{New(1, 2), New(1, 3), rat128.OverflowApprox, New(5, 6), nil},
+		{New(M, 1), New(1, 1), rat128.OverflowError, Zero, rat128.ErrNumOverflow},
+		{New(M, 1), New(1, 1), rat128.OverflowSaturate, New(M, 1), nil},
+		{New(M, 1), New(1, 1), rat128.OverflowApprox, New(M, 1), nil},
+		{New(-M, 1), New(-1, 2), rat128.OverflowSaturate, New(-M, 1), nil},
+		{New(-M, 1), New(-1, 2), rat128.OverflowApprox, New(-M, 1), nil},
+		{New(1, 1<<32), New(1, 1<<32-1), rat128.OverflowError, Zero, rat128.ErrDenOverflow},
+		{New(1, 1<<32), New(1, 1<<32-1), rat128.OverflowSaturate, Zero, rat128.ErrDenOverflow},
+		{New(1, 1<<32), New(1, 1<<32-1), rat128.OverflowApprox, New(4, 1<<33-1), nil},
+		{New(M-1, 2), New(1, 3), rat128.OverflowError, Zero, rat128.ErrNumOverflow},
+		{New(M-1, 2), New(1, 3), rat128.OverflowSaturate, Zero, rat128.ErrNumOverflow},
+		{New(M-1, 2), New(1, 3), rat128.OverflowApprox, New(M, 2), nil},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s)+(%s):%d", c.X.RationalString("_"), c.Y.RationalString("_"), c.Policy), func(t *testing.T) {
+			z, err := c.X.AddWith(c.Y, c.Policy)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if c.Err == nil && z != c.Z {
+				t.Errorf("got %v, want %v", z, c.Z)
+			}
+		})
+	}
+}
+
+func TestN_MulWith(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		X, Y   rat128.N
+		Policy rat128.OverflowPolicy
+		Z      rat128.N
+		Err    error
+	}{
+		{New(2, 3), New(3, 4), rat128.OverflowApprox, New(1, 2), nil},
+		{New(M, 1), New(-2, 1), rat128.OverflowError, Zero, rat128.ErrNumOverflow},
+		{New(M, 1), New(-2, 1), rat128.OverflowSaturate, New(-M, 1), nil},
+		{New(1, M), New(1, 2), rat128.OverflowSaturate, Zero, rat128.ErrDenOverflow},
+		{New(1, M), New(1, 2), rat128.OverflowApprox, New(0, 1), nil},
+		{New(1, M), New(2, 3), rat128.OverflowApprox, New(1, M), nil},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s)*(%s):%d", c.X.RationalString("_"), c.Y.RationalString("_"), c.Policy), func(t *testing.T) {
+			z, err := c.X.MulWith(c.Y, c.Policy)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if c.Err == nil && z != c.Z {
+				t.Errorf("got %v, want %v", z, c.Z)
+			}
+		})
+	}
+}
+
+func TestN_DivWith(t *testing.T) {
+	for _, policy := range []rat128.OverflowPolicy{rat128.OverflowError, rat128.OverflowSaturate, rat128.OverflowApprox} {
+		if _, err := New(1, 1).DivWith(Zero, policy); err != rat128.ErrDivByZero {
+			t.Errorf("policy %d: got error %v, want %v", policy, err, rat128.ErrDivByZero)
+		}
+	}
+	if z, err := New(math.MaxInt64, 1).DivWith(New(1, 2), rat128.OverflowSaturate); err != nil || z != New(math.MaxInt64, 1) {
+		t.Errorf("got (%v, %v), want (%v, nil)", z, err, New(math.MaxInt64, 1))
+	}
+	if z, err := New(1, 2).SubWith(New(-math.MaxInt64, 1), rat128.OverflowApprox); err != nil || z != New(math.MaxInt64, 1) {
+		t.Errorf("got (%v, %v), want (%v, nil)", z, err, New(math.MaxInt64, 1))
+	}
+}
+
+func TestN_RemainingHeadroom(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		X, Up, Down rat128.N
+	}{
+		{New(0, 1), New(M, 1), New(M, 1)},
+		{New(1, 2), New(M, 1), New(M, 1)},
+		{New(-1, 2), New(M, 1), New(M, 1)},
+		{New(5, 1), New(M-5, 1), New(M, 1)},
+		{New(7, 2), New(M-3, 1), New(M, 1)},
+		{New(-7, 2), New(M, 1), New(M-3, 1)},
+		{New(M, 1), New(0, 1), New(M, 1)},
+		{New(-M, 1), New(M, 1), New(0, 1)},
+		{New(M, 2), New(M-M/2, 1), New(M, 1)},
+	}
+	for _, c := range cases {
+		t.Run(c.X.RationalString("_"), func(t *testing.T) {
+			up, down := c.X.RemainingHeadroom()
+			if up != c.Up || down != c.Down {
+				t.Errorf("got (%v, %v), want (%v, %v)", up, down, c.Up, c.Down)
+			}
+			// anything beyond the headroom must overflow
+			if up != New(M, 1) {
+				if _, err := c.X.TryAdd(up.AddInt64(1)); err == nil {
+					t.Errorf("adding %v did not overflow", up.AddInt64(1))
+				}
+			}
+			if down != New(M, 1) {
+				if _, err := c.X.TrySub(down.AddInt64(1)); err == nil {
+					t.Errorf("subtracting %v did not overflow", down.AddInt64(1))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package rat128
+
+import "fmt"
+
+// EvalPoly returns the value of the polynomial with the given coefficients at
+// x, where coeffs[i] is the coefficient of x^i, so that
+//
+//	EvalPoly(coeffs, x) == coeffs[0] + coeffs[1]*x + ... + coeffs[k]*x^k
+//
+// The polynomial is evaluated with Horner's method. An empty polynomial is
+// zero everywhere. If an intermediate value overflows, the error identifies
+// the coefficient at which it happened.
+func EvalPoly(coeffs []N, x N) (N, error) {
+	var y N
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		var err error
+		if y, err = y.TryMul(x); err == nil {
+			y, err = y.TryAdd(coeffs[i])
+		}
+		if err != nil {
+			return N{}, fmt.Errorf("evaluating coefficient %d: %w", i, err)
+		}
+	}
+	return y, nil
+}
+
+// EvalRationalFunction returns P(x)/Q(x), where P and Q are the polynomials
+// with the coefficients num and den, respectively, as described by EvalPoly.
+// EvalRationalFunction returns ErrDivByZero if Q(x) is zero, and an overflow
+// error if evaluating either polynomial or dividing them overflows.
+func EvalRationalFunction(num, den []N, x N) (N, error) {
+	p, err := EvalPoly(num, x)
+	if err != nil {
+		return N{}, fmt.Errorf("numerator: %w", err)
+	}
+	q, err := EvalPoly(den, x)
+	if err != nil {
+		return N{}, fmt.Errorf("denominator: %w", err)
+	}
+	if q.IsZero() {
+		return N{}, ErrDivByZero
+	}
+	return p.TryDiv(q)
+}
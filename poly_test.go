@@ -0,0 +1,64 @@
+package rat128_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/kbolino/rat128"
+)
+
+func TestEvalPoly(t *testing.T) {
+	cases := []struct {
+		Coeffs []rat128.N
+		X, Y   rat128.N
+		Err    error
+	}{
+		{nil, New(3, 1), Zero, nil},
+		{[]rat128.N{New(5, 2)}, New(3, 1), New(5, 2), nil},
+		{[]rat128.N{New(1, 1), New(2, 1), New(3, 1)}, New(2, 1), New(17, 1), nil},
+		{[]rat128.N{New(1, 1), New(2, 1), New(3, 1)}, New(-1, 2), New(3, 4), nil},
+		{[]rat128.N{Zero, Zero, New(1, 2)}, New(2, 3), New(2, 9), nil},
+		{[]rat128.N{Zero, New(1, 1)}, New(math.MaxInt64, 1), New(math.MaxInt64, 1), nil},
+		{[]rat128.N{Zero, Zero, New(1, 1)}, New(1<<32, 1), Zero, rat128.ErrNumOverflow},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%v@%v", c.Coeffs, c.X), func(t *testing.T) {
+			y, err := rat128.EvalPoly(c.Coeffs, c.X)
+			if !errors.Is(err, c.Err) {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if y != c.Y {
+				t.Errorf("got %v, want %v", y, c.Y)
+			}
+		})
+	}
+}
+
+func TestEvalRationalFunction(t *testing.T) {
+	// (x+1)/(x^2-1/4)
+	num := []rat128.N{New(1, 1), New(1, 1)}
+	den := []rat128.N{New(-1, 4), Zero, New(1, 1)}
+	cases := []struct {
+		X, Y rat128.N
+		Err  error
+	}{
+		{New(0, 1), New(-4, 1), nil},
+		{New(1, 1), New(8, 3), nil},
+		{New(-1, 1), New(0, 1), nil},
+		{New(3, 2), New(5, 4), nil},
+		{New(1, 2), Zero, rat128.ErrDivByZero},
+		{New(-1, 2), Zero, rat128.ErrDivByZero},
+		{New(1<<32, 1), Zero, rat128.ErrNumOverflow},
+	}
+	for _, c := range cases {
+		t.Run(c.X.String(), func(t *testing.T) {
+			y, err := rat128.EvalRationalFunction(num, den, c.X)
+			if !errors.Is(err, c.Err) {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if y != c.Y {
+				t.Errorf("got %v, want %v", y, c.Y)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package rat128
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+)
+
+// RandN returns a pseudo-random valid rational number drawn from r.
+// The numerator and denominator are drawn uniformly from the ranges
+// [-math.MaxInt64, math.MaxInt64] and [1, math.MaxInt64], respectively, and
+// the result is then reduced. This is suitable for testing and benchmarking
+// but is not uniform over the valid values of N.
+func RandN(r *rand.Rand) N {
+	num := r.Int63()
+	if r.Intn(2) == 0 {
+		num = -num
+	}
+	den := r.Int63n(math.MaxInt64) + 1
+	return New(num, den)
+}
+
+// RandNInRange returns a pseudo-random valid rational number drawn from r
+// that lies in the closed interval [lo, hi]. The bounds may be given in
+// either order.
+//
+// A denominator d is drawn uniformly from [1, math.MaxInt64] and then a
+// numerator is drawn uniformly from those that put the result in [lo, hi].
+// If the interval is so narrow that repeated draws of d turn up no such
+// numerator, one of lo or hi is returned instead.
+func RandNInRange(r *rand.Rand, lo, hi N) N {
+	if lo.Cmp(hi) > 0 {
+		lo, hi = hi, lo
+	}
+	if lo == hi {
+		return lo
+	}
+	bigLo, bigHi := lo.BigRat(), hi.BigRat()
+	maxNum, minNum := big.NewInt(math.MaxInt64), big.NewInt(-math.MaxInt64)
+	var bigD, kLo, kHi, mod big.Int
+	var t big.Rat
+	for attempt := 0; attempt < 64; attempt++ {
+		d := r.Int63n(math.MaxInt64) + 1
+		bigD.SetInt64(d)
+		// kLo = ceil(lo*d), clamped to the smallest valid numerator
+		t.Mul(bigLo, t.SetInt(&bigD))
+		kLo.DivMod(t.Num(), t.Denom(), &mod)
+		if mod.Sign() != 0 {
+			kLo.Add(&kLo, big.NewInt(1))
+		}
+		if kLo.Cmp(minNum) < 0 {
+			kLo.Set(minNum)
+		}
+		// kHi = floor(hi*d), clamped to the largest valid numerator
+		t.Mul(bigHi, t.SetInt(&bigD))
+		kHi.Div(t.Num(), t.Denom())
+		if kHi.Cmp(maxNum) > 0 {
+			kHi.Set(maxNum)
+		}
+		if kLo.Cmp(&kHi) > 0 {
+			continue
+		}
+		// k is uniform in [kLo, kHi]
+		k := kHi.Sub(&kHi, &kLo)
+		k.Add(k, big.NewInt(1))
+		k.Rand(r, k)
+		k.Add(k, &kLo)
+		return New(k.Int64(), d)
+	}
+	if r.Intn(2) == 0 {
+		return lo
+	}
+	return hi
+}
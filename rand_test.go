@@ -0,0 +1,77 @@
+package rat128_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/kbolino/rat128"
+)
+
+func TestRandN(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x := rat128.RandN(r)
+		if !x.IsValid() {
+			t.Fatalf("got invalid value %v", x)
+		}
+	}
+}
+
+func TestRandNInRange(t *testing.T) {
+	cases := []struct {
+		Lo, Hi rat128.N
+	}{
+		{New(0, 1), New(1, 1)},
+		{New(1, 1), New(0, 1)},
+		{New(-1, 3), New(1, 7)},
+		{New(1, 2), New(1, 2)},
+		{New(-math.MaxInt64, 1), New(math.MaxInt64, 1)},
+		{New(math.MaxInt64-1, 1), New(math.MaxInt64, 1)},
+		{New(1, math.MaxInt64), New(1, math.MaxInt64-1)},
+	}
+	r := rand.New(rand.NewSource(1))
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("[%s,%s]", c.Lo.RationalString("_"), c.Hi.RationalString("_")), func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				x := rat128.RandNInRange(r, c.Lo, c.Hi)
+				if !x.IsValid() {
+					t.Fatalf("got invalid value %v", x)
+				}
+				if !x.Between(c.Lo, c.Hi, true) && !x.Between(c.Hi, c.Lo, true) {
+					t.Fatalf("got %v, want value in range", x)
+				}
+			}
+		})
+	}
+}
+
+// TestRandArithmetic checks basic arithmetic on random values against big.Rat.
+func TestRandArithmetic(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	small := func() rat128.N {
+		return rat128.RandNInRange(r, New(-1<<20, 1), New(1<<20, 1))
+	}
+	for i := 0; i < 1000; i++ {
+		x, y := rat128.RandN(r), rat128.RandN(r)
+		if i%2 == 0 {
+			x, y = small(), small()
+		}
+		bx, by := x.BigRat(), y.BigRat()
+		if c, bc := x.Cmp(y), bx.Cmp(by); c != bc {
+			t.Errorf("(%v).Cmp(%v): got %d, want %d", x, y, c, bc)
+		}
+		if z, err := x.TryAdd(y); err == nil {
+			if bz := new(big.Rat).Add(bx, by); z.BigRat().Cmp(bz) != 0 {
+				t.Errorf("(%v)+(%v): got %v, want %v", x, y, z, bz)
+			}
+		}
+		if z, err := x.TryMul(y); err == nil {
+			if bz := new(big.Rat).Mul(bx, by); z.BigRat().Cmp(bz) != 0 {
+				t.Errorf("(%v)*(%v): got %v, want %v", x, y, z, bz)
+			}
+		}
+	}
+}
@@ -19,6 +19,10 @@ var (
 	ErrNumOverflow = errors.New("numerator overflow")
 	ErrDivByZero   = errors.New("division by zero")
 	ErrFmtInvalid  = errors.New("invalid number format")
+	ErrEmpty       = errors.New("empty input")
+	ErrInexact     = errors.New("value is not exactly representable")
+	ErrNotReduced  = errors.New("not in reduced form")
+	ErrInvalid     = errors.New("invalid argument")
 )
 
 // N is a rational number with 64-bit numerator and denominator.
@@ -191,6 +195,189 @@ func ParseDecimalString(s string) (N, error) {
 	return result, nil
 }
 
+// ParseMixedString parses a string representation of a mixed number, in one
+// of the forms "W", "m/n", or "W m/n", where W, m, and n are integers in base
+// 10 and only W (or m in the second form) may be negative (indicated with
+// leading hyphen). In the third form, the fraction must be proper, with
+// 0 < m < n. A negative sign on W applies to the whole value, so "-2 1/3" is
+// -7/3. This is the inverse of MixedString.
+// ParseMixedString returns an error wrapping ErrFmtInvalid if s is not in
+// one of these forms, ErrDenInvalid if n is zero, and an overflow error if
+// the result does not fit.
+func ParseMixedString(s string) (N, error) {
+	if strings.Contains(s, "+") {
+		return N{}, ErrFmtInvalid
+	}
+	fields := strings.Fields(s)
+	switch len(fields) {
+	case 1:
+		if strings.Contains(fields[0], "/") {
+			x, err := ParseRationalString(fields[0])
+			var numErr *strconv.NumError
+			if errors.As(err, &numErr) {
+				return N{}, fmt.Errorf("%w: %w", ErrFmtInvalid, err)
+			}
+			return x, err
+		}
+		w, err := parseMixedPart("whole part", fields[0])
+		if err != nil {
+			return N{}, err
+		}
+		return Try(w, 1)
+	case 2:
+		whole, frac := fields[0], fields[1]
+		neg := strings.HasPrefix(whole, "-")
+		if neg {
+			whole = whole[1:]
+		}
+		if strings.HasPrefix(whole, "-") || strings.HasPrefix(frac, "-") {
+			return N{}, ErrFmtInvalid
+		}
+		num, den, ok := strings.Cut(frac, "/")
+		if !ok {
+			return N{}, ErrFmtInvalid
+		}
+		w, err := parseMixedPart("whole part", whole)
+		if err != nil {
+			return N{}, err
+		}
+		m, err := parseMixedPart("numerator", num)
+		if err != nil {
+			return N{}, err
+		}
+		n, err := parseMixedPart("denominator", den)
+		if err != nil {
+			return N{}, err
+		}
+		if n <= 0 {
+			return N{}, ErrDenInvalid
+		} else if m <= 0 || m >= n {
+			return N{}, ErrFmtInvalid
+		}
+		x, err := N{w, 0}.TryAdd(New(m, n))
+		if err != nil {
+			return N{}, err
+		}
+		if neg {
+			x = x.Neg()
+		}
+		return x, nil
+	}
+	return N{}, ErrFmtInvalid
+}
+
+// parseMixedPart parses s as an integer in base 10 for ParseMixedString,
+// wrapping any error with ErrFmtInvalid and the name of the part.
+func parseMixedPart(name, s string) (int64, error) {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: parsing %s: %w", ErrFmtInvalid, name, err)
+	}
+	return v, nil
+}
+
+// ParseBigRatCompatible parses s with the same grammar as big.Rat.SetString,
+// which accepts fractions like "3/4" as well as decimal and floating-point
+// forms like "0.75", "7.5e-1", and "0x1.8p-1", by parsing it with big.Rat and
+// then converting the result with FromBigRat.
+// ParseBigRatCompatible returns ErrFmtInvalid if big.Rat rejects s, and an
+// overflow error if the value does not fit.
+func ParseBigRatCompatible(s string) (N, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return N{}, ErrFmtInvalid
+	}
+	return FromBigRat(r)
+}
+
+// ParseRationalFields splits line into fields separated by sep and parses
+// each one, after trimming surrounding whitespace, with ParseRationalString
+// if it contains a slash or with ParseDecimalString otherwise. If sep is
+// empty, the fields are separated by runs of whitespace instead.
+// If any field cannot be parsed, the error identifies the first such field.
+func ParseRationalFields(line, sep string) ([]N, error) {
+	var fields []string
+	if sep == "" {
+		fields = strings.Fields(line)
+	} else {
+		fields = strings.Split(line, sep)
+	}
+	xs := make([]N, len(fields))
+	for i, field := range fields {
+		field = strings.TrimSpace(field)
+		var err error
+		if strings.Contains(field, "/") {
+			xs[i], err = ParseRationalString(field)
+		} else {
+			xs[i], err = ParseDecimalString(field)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing field %d: %w", i, err)
+		}
+	}
+	return xs, nil
+}
+
+// FromDigits builds a rational number from its decimal digits, given as the
+// ASCII characters '0' through '9', with the decimal point placed after the
+// first pointIndex digits. A pointIndex less than 0 or greater than
+// len(digits) places the point that many positions outside of the digits, as
+// if padded with zeroes. The result is negated if negative is true.
+// For example, FromDigits([]byte("1234"), 1, true) is -1234/1000 = -617/500.
+// FromDigits returns ErrFmtInvalid if any byte is not a digit or if there are
+// no digits, and an overflow error if the result does not fit.
+func FromDigits(digits []byte, pointIndex int, negative bool) (N, error) {
+	if len(digits) == 0 {
+		return N{}, ErrFmtInvalid
+	}
+	for _, d := range digits {
+		if d < '0' || d > '9' {
+			return N{}, ErrFmtInvalid
+		}
+	}
+	m, ok := new(big.Int).SetString(string(digits), 10)
+	if !ok {
+		return N{}, ErrFmtInvalid
+	}
+	if negative {
+		m.Neg(m)
+	}
+	r := new(big.Rat)
+	if e := pointIndex - len(digits); e >= 0 {
+		r.SetInt(m.Mul(m, pow10Big(e)))
+	} else {
+		r.SetFrac(m, pow10Big(-e))
+	}
+	return FromBigRat(r)
+}
+
+// FromBits is like FromDigits but for binary digits, where each element of
+// bits is a 1 if true or a 0 if false, and the result is never negative. The
+// result is a dyadic rational, with a denominator that is a power of two.
+// For example, FromBits([]bool{true, false, true, true}, 2) is 10.11 in
+// binary, which is 11/4.
+// FromBits returns ErrFmtInvalid if there are no bits, and an overflow error
+// if the result does not fit, which is always the case if there are more than
+// 63 significant bits.
+func FromBits(bits []bool, pointIndex int) (N, error) {
+	if len(bits) == 0 {
+		return N{}, ErrFmtInvalid
+	}
+	m := new(big.Int)
+	for i, b := range bits {
+		if b {
+			m.SetBit(m, len(bits)-1-i, 1)
+		}
+	}
+	r := new(big.Rat)
+	if e := pointIndex - len(bits); e >= 0 {
+		r.SetInt(m.Lsh(m, uint(e)))
+	} else {
+		r.SetFrac(m, new(big.Int).Lsh(big.NewInt(1), uint(-e)))
+	}
+	return FromBigRat(r)
+}
+
 // FromFloat64 extracts a rational number from a float64. The result will be
 // exactly equal to v, or else an error will be returned.
 func FromFloat64(v float64) (N, error) {
@@ -237,6 +424,38 @@ func FromFloat64(v float64) (N, error) {
 	return Try(s*m, 1<<-e)
 }
 
+// ClosestFromFloat64 returns the valid rational number nearest to v, along
+// with the residual v-x rounded to the nearest float64. If v can be
+// converted exactly, the result is the same as FromFloat64 and the residual
+// is 0. Values of v too large in magnitude are clamped to ±math.MaxInt64.
+// ClosestFromFloat64 returns ErrInexact if v is NaN or infinite.
+func ClosestFromFloat64(v float64) (x N, residual float64, err error) {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return N{}, 0, ErrInexact
+	}
+	x, err = FromFloat64(v)
+	switch err {
+	case nil:
+		return x, 0, nil
+	case ErrNumOverflow:
+		// v is an integer too large for the numerator
+		x = N{math.MaxInt64, 0}
+		if v < 0 {
+			x = x.Neg()
+		}
+	default:
+		// v is too small for the denominator, so find the nearest fraction
+		// whose denominator fits; the numerator is small enough to fit
+		x, err = ApproxBigRat(new(big.Rat).SetFloat64(v), math.MaxInt64)
+		if err != nil {
+			return N{}, 0, err
+		}
+	}
+	r := new(big.Rat).SetFloat64(v)
+	residual, _ = r.Sub(r, x.BigRat()).Float64()
+	return x, residual, nil
+}
+
 // FromBigRat converts a big.Rat to N, if it is possible to do so.
 func FromBigRat(r *big.Rat) (N, error) {
 	num, den := r.Num(), r.Denom()
@@ -248,6 +467,56 @@ func FromBigRat(r *big.Rat) (N, error) {
 	return Try(num.Int64(), den.Int64())
 }
 
+// TryReduceOrBig returns num/den in reduced form as an N and a nil big.Rat if
+// it fits, or else as a zero N and a new big.Rat. In the common case that
+// num and den fit in int64, no big.Rat is allocated.
+// TryReduceOrBig panics if den is zero, like big.Rat.SetFrac.
+// There is no separate accessor for narrowing the big.Rat back to N later;
+// FromBigRat is the intended way to do that.
+func TryReduceOrBig(num, den *big.Int) (N, *big.Rat) {
+	if num.IsInt64() && den.IsInt64() && den.Sign() > 0 {
+		if x, err := Try(num.Int64(), den.Int64()); err == nil {
+			return x, nil
+		}
+	}
+	r := new(big.Rat).SetFrac(num, den)
+	if x, err := FromBigRat(r); err == nil {
+		return x, nil
+	}
+	return N{}, r
+}
+
+// FromBigFloat converts a big.Float to N exactly, if it is possible to do so.
+// Since a finite big.Float is always a dyadic rational, this is the case
+// whenever its numerator and denominator both fit.
+// FromBigFloat returns ErrNumOverflow if f is infinite.
+func FromBigFloat(f *big.Float) (N, error) {
+	if f.IsInf() {
+		return N{}, ErrNumOverflow
+	} else if f.Sign() == 0 {
+		return N{}, nil
+	}
+	// decompose f such that f = m*2^e with m an odd integer
+	prec := int(f.MinPrec())
+	mant := new(big.Float)
+	e := f.MantExp(mant) - prec
+	m, _ := mant.SetMantExp(mant, prec).Int(nil)
+	if e >= 0 {
+		// f is an integer
+		if m.BitLen()+e > 63 {
+			return N{}, ErrNumOverflow
+		}
+		return Try(m.Lsh(m, uint(e)).Int64(), 1)
+	}
+	// else, f is not an integer
+	if m.BitLen() > 63 {
+		return N{}, ErrNumOverflow
+	} else if e <= -63 {
+		return N{}, ErrDenOverflow
+	}
+	return tryAlreadyReduced(m.Int64(), 1<<-e)
+}
+
 // Num returns the numerator of x.
 func (x N) Num() int64 {
 	return x.m
@@ -258,6 +527,23 @@ func (x N) Den() int64 {
 	return x.n + 1
 }
 
+// AsRatioOf returns the numerator of x when expressed over the denominator
+// unitDen, such that x == num/unitDen, and true. If there is no such integer
+// numerator (that is, if x.Den() does not divide unitDen), if it would
+// overflow int64, or if unitDen is not positive, AsRatioOf returns 0 and
+// false.
+func (x N) AsRatioOf(unitDen int64) (num int64, ok bool) {
+	if unitDen <= 0 || unitDen%x.Den() != 0 {
+		return 0, false
+	}
+	k := unitDen / x.Den()
+	hi, lo := bits.Mul64(uint64(abs64(x.Num())), uint64(k))
+	if hi != 0 || lo > math.MaxInt64 {
+		return 0, false
+	}
+	return sgn64(x.Num()) * int64(lo), true
+}
+
 // IsValid returns true if x is a valid rational number.
 // Invalid numbers do not arise under normal circumstances, but may occur if
 // a value is constructed or manipulated using unsafe operations.
@@ -271,6 +557,32 @@ func (x N) IsValid() bool {
 	return true
 }
 
+// CheckInvariants returns a descriptive error if x is not a valid rational
+// number, or nil if it is. It checks the same invariants as IsValid, that
+// the denominator is positive, the numerator is not math.MinInt64, and x is
+// in reduced form, and also that x survives a round trip through String and
+// ParseRationalString. The returned error wraps ErrDenInvalid, ErrNumOverflow,
+// ErrNotReduced, or ErrFmtInvalid, as appropriate.
+func CheckInvariants(x N) error {
+	if x.n < 0 || x.n == math.MaxInt64 {
+		return fmt.Errorf("%w: biased denominator is %d", ErrDenInvalid, x.n)
+	}
+	if x.m == math.MinInt64 {
+		return fmt.Errorf("%w: numerator is %d", ErrNumOverflow, x.m)
+	}
+	if d := GCD(abs64(x.Num()), x.Den()); d != 1 && x.m != 0 {
+		return fmt.Errorf("%w: %s has common factor %d", ErrNotReduced, x, d)
+	} else if x.m == 0 && x.n != 0 {
+		return fmt.Errorf("%w: %s is zero but denominator is not 1", ErrNotReduced, x)
+	}
+	if y, err := ParseRationalString(x.String()); err != nil {
+		return fmt.Errorf("%w: parsing %s: %v", ErrFmtInvalid, x, err)
+	} else if y != x {
+		return fmt.Errorf("%w: %s parsed as %s", ErrFmtInvalid, x, y)
+	}
+	return nil
+}
+
 // IsZero returns true if x is equal to 0.
 func (x N) IsZero() bool {
 	return x.m == 0
@@ -311,17 +623,185 @@ func (x N) Inv() N {
 	return y
 }
 
+// IsReciprocalOf returns true if x*y == 1.
+// Since valid values are in reduced form, this is true exactly when the
+// signs of x and y agree and the numerator of each is the denominator of the
+// other, so unlike x.Mul(y) it never overflows.
+func (x N) IsReciprocalOf(y N) bool {
+	return x.m != 0 && x.Sign() == y.Sign() && abs64(x.Num()) == y.Den() && abs64(y.Num()) == x.Den()
+}
+
 // Abs returns the absolute value of x, |x|.
 func (x N) Abs() N {
 	return N{abs64(x.m), x.n}
 }
 
 // Cmp returns -1 if x < y, 0 if x == y, and 1 if x > y.
+// Unlike x.Sub(y).Sign(), Cmp never overflows or panics.
 func (x N) Cmp(y N) int {
 	if x == y {
 		return 0
 	}
-	return x.Sub(y).Sign()
+	// Values with different signs can be ordered by sign alone.
+	sx, sy := x.Sign(), y.Sign()
+	if sx != sy {
+		if sx < sy {
+			return -1
+		}
+		return 1
+	}
+	// The signs are the same and nonzero (otherwise x == y), so compare the
+	// magnitudes by cross-multiplying with 128-bit precision, then flip the
+	// result if both are negative.
+	h1, l1 := bits.Mul64(uint64(abs64(x.Num())), uint64(y.Den()))
+	h2, l2 := bits.Mul64(uint64(abs64(y.Num())), uint64(x.Den()))
+	return sx * cmp128(h1, l1, h2, l2)
+}
+
+// CmpDecimalString compares x with the exact value of the decimal string s
+// and returns -1, 0, or 1 in the manner of Cmp. The string may have more
+// digits than fit in N, such as "0.123456789012345678901234567890", and may
+// use any form accepted by big.Rat.SetString, including exponents.
+// CmpDecimalString returns ErrFmtInvalid if s cannot be parsed.
+func (x N) CmpDecimalString(s string) (int, error) {
+	if y, err := ParseDecimalString(s); err == nil {
+		return x.Cmp(y), nil
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, ErrFmtInvalid
+	}
+	return x.BigRat().Cmp(r), nil
+}
+
+// Eq returns true if x == y. For valid values, this is the same as x == y.
+func (x N) Eq(y N) bool {
+	return x == y
+}
+
+// EqualMagnitude returns true if |x| == |y|. Since valid values are reduced,
+// this only compares the absolute values of the numerators and the
+// denominators.
+func (x N) EqualMagnitude(y N) bool {
+	return x.n == y.n && abs64(x.m) == abs64(y.m)
+}
+
+// EqualMagnitude returns true if |x| == |y|, like N.EqualMagnitude.
+func EqualMagnitude(x, y N) bool {
+	return x.EqualMagnitude(y)
+}
+
+// Lt returns true if x < y.
+func (x N) Lt(y N) bool {
+	return x.Cmp(y) < 0
+}
+
+// Lte returns true if x <= y.
+func (x N) Lte(y N) bool {
+	return x.Cmp(y) <= 0
+}
+
+// Gt returns true if x > y.
+func (x N) Gt(y N) bool {
+	return x.Cmp(y) > 0
+}
+
+// Gte returns true if x >= y.
+func (x N) Gte(y N) bool {
+	return x.Cmp(y) >= 0
+}
+
+// Between returns true if x lies between lo and hi. If inclusive is true,
+// the interval is closed, [lo, hi]; otherwise, it is open, (lo, hi).
+// If lo > hi, the interval is empty and Between always returns false.
+func (x N) Between(lo, hi N, inclusive bool) bool {
+	cl, ch := x.Cmp(lo), x.Cmp(hi)
+	if inclusive {
+		return cl >= 0 && ch <= 0
+	}
+	return cl > 0 && ch < 0
+}
+
+// PositionRatio returns (x-lo)/(hi-lo), the position of x relative to the
+// interval from lo to hi, which is in [0, 1] if x lies in that interval.
+// The result is exact: if an intermediate difference overflows, it is
+// computed with big.Rat, and an error is returned only if the result does
+// not fit. PositionRatio returns ErrDivByZero if lo == hi.
+func (x N) PositionRatio(lo, hi N) (N, error) {
+	if lo == hi {
+		return N{}, ErrDivByZero
+	}
+	num, err := x.TrySub(lo)
+	if err == nil {
+		var den N
+		if den, err = hi.TrySub(lo); err == nil {
+			return num.TryDiv(den)
+		}
+	}
+	lr := lo.BigRat()
+	r := new(big.Rat).Sub(x.BigRat(), lr)
+	return FromBigRat(r.Quo(r, lr.Sub(hi.BigRat(), lr)))
+}
+
+// UnlerpClamped is like PositionRatio but clamps the result to [0, 1], so it
+// returns 0 if x is at or beyond lo and 1 if x is at or beyond hi. It works
+// out which of these applies by comparison alone, so it only returns an
+// overflow error if x is strictly between lo and hi and the result does not
+// fit. UnlerpClamped returns ErrDivByZero if lo == hi.
+func (x N) UnlerpClamped(lo, hi N) (N, error) {
+	dir := hi.Cmp(lo)
+	switch {
+	case dir == 0:
+		return N{}, ErrDivByZero
+	case x.Cmp(lo) != dir:
+		return N{}, nil
+	case hi.Cmp(x) != dir:
+		return N{1, 0}, nil
+	}
+	return x.PositionRatio(lo, hi)
+}
+
+// ClampUnit returns x clamped to the unit interval [0, 1]; that is, it
+// returns 0 if x < 0, 1 if x > 1, and x otherwise.
+func (x N) ClampUnit() N {
+	if x.m < 0 {
+		return N{}
+	} else if x.m > x.n {
+		// m > n means x >= 1, since x is reduced and so m == n+1 only if x == 1
+		return N{1, 0}
+	}
+	return x
+}
+
+// IsProbability returns true if x lies in the unit interval [0, 1].
+func (x N) IsProbability() bool {
+	return x.ClampUnit() == x
+}
+
+// ProbabilityFromOdds interprets x as the betting odds m:n against an event
+// and returns the probability of that event, n/(m+n), which is 1/(1+x). For
+// example, odds of 3/2 give a probability of 2/5.
+// ProbabilityFromOdds returns ErrDivByZero if x == -1 and an overflow error
+// if m+n overflows.
+func (x N) ProbabilityFromOdds() (N, error) {
+	d, err := x.TryAdd(N{1, 0})
+	if err != nil {
+		return N{}, err
+	}
+	return d.TryInv()
+}
+
+// OddsFromProbability interprets x as the probability of an event and returns
+// the betting odds against that event, (1-x)/x. This is the inverse of
+// ProbabilityFromOdds.
+// OddsFromProbability returns ErrDivByZero if x == 0, since the odds would be
+// infinite, and an overflow error if 1-x overflows.
+func (x N) OddsFromProbability() (N, error) {
+	d, err := N{1, 0}.TrySub(x)
+	if err != nil {
+		return N{}, err
+	}
+	return d.TryDiv(x)
 }
 
 // TryAdd adds x and y and returns the result.
@@ -511,6 +991,157 @@ func (x N) Mul(y N) N {
 	return z
 }
 
+// MulHeadroom returns how many bits of slack there are in x*y, which is 63
+// minus the bit length of the larger of its numerator and denominator in
+// lowest terms, and true if that is not negative. Thus, ok is true exactly
+// when TryMul would succeed, and slack says how many bits short of failing
+// it came, or by how many bits it would fail if negative. Like TryMul,
+// MulHeadroom reduces by the cross-GCDs first, so it never needs to compute
+// the product itself.
+func (x N) MulHeadroom(y N) (slack int, ok bool) {
+	if x.m == 0 || y.m == 0 {
+		// the product is 0/1
+		return 62, true
+	}
+	mx, nx := abs64(x.Num()), x.Den()
+	my, ny := abs64(y.Num()), y.Den()
+	if d := GCD(mx, ny); d != 1 {
+		mx, ny = mx/d, ny/d
+	}
+	if d := GCD(my, nx); d != 1 {
+		my, nx = my/d, nx/d
+	}
+	slack = 63 - max(mulLen64(mx, my), mulLen64(nx, ny))
+	return slack, slack >= 0
+}
+
+// mulLen64 returns the bit length of a*b, which must be non-negative.
+func mulLen64(a, b int64) int {
+	hi, lo := bits.Mul64(uint64(a), uint64(b))
+	if hi != 0 {
+		return 64 + bits.Len64(hi)
+	}
+	return bits.Len64(lo)
+}
+
+// TrySqr squares x and returns the result.
+// The following are equivalent in outcome, but TrySqr is faster:
+//
+//	x.TrySqr() == x.TryMul(x)
+func (x N) TrySqr() (N, error) {
+	// Since x is already reduced, x*x is also reduced and non-negative, so
+	// we can skip the sign and cross-GCD steps that TryMul has to perform.
+	m, n := uint64(abs64(x.Num())), uint64(x.Den())
+	mh, ml := bits.Mul64(m, m)
+	if mh > 0 || ml > math.MaxInt64 {
+		return N{}, ErrNumOverflow
+	}
+	nh, nl := bits.Mul64(n, n)
+	if nh > 0 || nl > math.MaxInt64 {
+		return N{}, ErrDenOverflow
+	}
+	return N{int64(ml), int64(nl) - 1}, nil
+}
+
+// Sqr squares x and returns the result.
+// Sqr panics if the result would overflow.
+func (x N) Sqr() N {
+	z, err := x.TrySqr()
+	if err != nil {
+		panic(err)
+	}
+	return z
+}
+
+// ExactSqrt returns the square root of x and true if it is rational, which
+// is when x is non-negative and both its numerator and denominator are
+// perfect squares. Otherwise, it returns 0 and false.
+func (x N) ExactSqrt() (N, bool) {
+	if x.m < 0 {
+		return N{}, false
+	}
+	m, ok := isqrt64(x.Num())
+	if !ok {
+		return N{}, false
+	}
+	n, ok := isqrt64(x.Den())
+	if !ok {
+		return N{}, false
+	}
+	// the square roots of coprime integers are also coprime
+	return N{m, n - 1}, true
+}
+
+// TryPowRat returns x raised to the rational power exp = p/q, which is the
+// q-th root of x raised to the power p. For example, (8/27)^(2/3) is 4/9.
+// Any number raised to the power 0 is 1, including 0.
+// TryPowRat returns ErrInexact if the result is not rational, which is when
+// the numerator or denominator of x is not a perfect q-th power or when x is
+// negative and q is even, ErrDivByZero if x is 0 and p is negative, and an
+// overflow error if the result does not fit.
+func (x N) TryPowRat(exp N) (N, error) {
+	p, q := exp.Num(), exp.Den()
+	switch {
+	case p == 0:
+		return N{1, 0}, nil
+	case x.m == 0:
+		if p < 0 {
+			return N{}, ErrDivByZero
+		}
+		return N{}, nil
+	case x.m < 0 && q%2 == 0:
+		return N{}, ErrInexact
+	}
+	// taking the root first keeps the intermediate values small
+	m, ok := iroot64(abs64(x.Num()), q)
+	if !ok {
+		return N{}, ErrInexact
+	}
+	n, ok := iroot64(x.Den(), q)
+	if !ok {
+		return N{}, ErrInexact
+	}
+	if p < 0 {
+		m, n, p = n, m, -p
+	}
+	// powers of coprime integers are also coprime
+	if m, ok = pow64(m, p); !ok {
+		return N{}, ErrNumOverflow
+	}
+	if n, ok = pow64(n, p); !ok {
+		return N{}, ErrDenOverflow
+	}
+	if x.m < 0 && p%2 != 0 {
+		m = -m
+	}
+	return N{m, n - 1}, nil
+}
+
+// GeometricMean2 returns the geometric mean of x and y, sqrt(x*y), and true
+// if it is rational, which is when x*y is the square of a rational number.
+// Otherwise, it returns 0 and false. The mean is never negative, even if x
+// and y are both negative. Although x*y may not fit in N, the mean always
+// does, so the product is computed with big.Int.
+// GeometricMean2 returns ErrInvalid if x*y is negative.
+func (x N) GeometricMean2(y N) (N, bool, error) {
+	if x.Sign()*y.Sign() < 0 {
+		return N{}, false, ErrInvalid
+	}
+	m := new(big.Int).Mul(big.NewInt(abs64(x.Num())), big.NewInt(abs64(y.Num())))
+	n := new(big.Int).Mul(big.NewInt(x.Den()), big.NewInt(y.Den()))
+	// reduce the product, then both parts must be perfect squares
+	g := new(big.Int).GCD(nil, nil, m, n)
+	m.Quo(m, g)
+	n.Quo(n, g)
+	sm, sn := new(big.Int).Sqrt(m), new(big.Int).Sqrt(n)
+	if g.Mul(sm, sm).Cmp(m) != 0 || g.Mul(sn, sn).Cmp(n) != 0 {
+		return N{}, false, nil
+	}
+	// sm and sn fit, since sm*sm <= |x.Num()*y.Num()| < 2^126 and likewise
+	// for sn
+	return N{sm.Int64(), sn.Int64() - 1}, true, nil
+}
+
 // TryDiv divides x by y and returns the result.
 // TryDiv returns 0 and a non-nil error for division by zero or if the result
 // would overflow.
@@ -530,17 +1161,336 @@ func (x N) Div(y N) N {
 	return x.Mul(y.Inv())
 }
 
+// TryMod returns x modulo y, which is x - y*floor(x/y). The result has the
+// same sign as y and a smaller magnitude, so for positive y it lies in
+// [0, y). The result is exact: if an intermediate value overflows, it is
+// computed with big.Rat, and an error is returned only if the result does
+// not fit. TryMod returns ErrDivByZero if y is zero.
+func (x N) TryMod(y N) (N, error) {
+	if y.IsZero() {
+		return N{}, ErrDivByZero
+	}
+	if q, err := x.TryDiv(y); err == nil {
+		if p, err := q.Round(RoundFloor).TryMul(y); err == nil {
+			if r, err := x.TrySub(p); err == nil {
+				return r, nil
+			}
+		}
+	}
+	xr, yr := x.BigRat(), y.BigRat()
+	q := new(big.Rat).Quo(xr, yr)
+	// the denominator is positive, so Euclidean division is floor division
+	k := new(big.Int).Div(q.Num(), q.Denom())
+	return FromBigRat(xr.Sub(xr, q.Mul(yr, q.SetInt(k))))
+}
+
+// Mod returns x modulo y, as described by TryMod.
+// Mod panics if y is zero or if the result would overflow.
+func (x N) Mod(y N) N {
+	z, err := x.TryMod(y)
+	if err != nil {
+		panic(err)
+	}
+	return z
+}
+
+// TryAvg2 returns the average of x and y, (x+y)/2, which is also their
+// midpoint. The result is exact: intermediate values may overflow, so TryAvg2
+// tries the equivalent forms (x+y)/2, x+(y-x)/2, and x/2+y/2 in turn, then
+// falls back on big.Rat, and returns an error only if the result does not
+// fit.
+func (x N) TryAvg2(y N) (N, error) {
+	if s, err := x.TryAdd(y); err == nil {
+		return s.TryDivInt64(2)
+	}
+	if d, err := y.TrySub(x); err == nil {
+		if h, err := d.TryDivInt64(2); err == nil {
+			if z, err := x.TryAdd(h); err == nil {
+				return z, nil
+			}
+		}
+	}
+	if hx, err := x.TryDivInt64(2); err == nil {
+		if hy, err := y.TryDivInt64(2); err == nil {
+			if z, err := hx.TryAdd(hy); err == nil {
+				return z, nil
+			}
+		}
+	}
+	r := new(big.Rat).Add(x.BigRat(), y.BigRat())
+	return FromBigRat(r.Quo(r, big.NewRat(2, 1)))
+}
+
+// Avg2 is like TryAvg2 but panics instead of returning an error.
+func (x N) Avg2(y N) N {
+	z, err := x.TryAvg2(y)
+	if err != nil {
+		panic(err)
+	}
+	return z
+}
+
+// Blend returns the convex combination x*(1-weight) + y*weight, which is x
+// when weight is 0 and y when weight is 1. A weight outside of [0, 1]
+// extrapolates beyond x or y. The result is exact: if an intermediate value
+// overflows, it is computed with big.Rat, and an error is returned only if
+// the result does not fit.
+func (x N) Blend(y, weight N) (N, error) {
+	switch weight {
+	case N{}:
+		return x, nil
+	case N{1, 0}:
+		return y, nil
+	}
+	if d, err := y.TrySub(x); err == nil {
+		if d, err = d.TryMul(weight); err == nil {
+			if z, err := x.TryAdd(d); err == nil {
+				return z, nil
+			}
+		}
+	}
+	xr, w := x.BigRat(), weight.BigRat()
+	d := new(big.Rat).Sub(y.BigRat(), xr)
+	return FromBigRat(d.Add(xr, d.Mul(d, w)))
+}
+
+// ReciprocalDiff returns 1/x - 1/y, as in the thin lens equation.
+// The naive form, x.Inv() minus y.Inv(), can overflow even when the result
+// fits, because TryAdd and TrySub only cancel factors shared by the
+// denominators, which here are the numerators of x and y. So, ReciprocalDiff
+// first tries the fused form ((y-x)/x)/y, where the divisions cancel factors
+// more thoroughly, then the naive form, then falls back on big.Rat, and
+// returns an error only if the result does not fit.
+// For example, with x = 2^20*2097155 and y = 2^20*5242883, the naive form
+// overflows but the fused form gives 3/10995138297865.
+// ReciprocalDiff returns ErrDivByZero if x or y is zero.
+func (x N) ReciprocalDiff(y N) (N, error) {
+	if x.IsZero() || y.IsZero() {
+		return N{}, ErrDivByZero
+	}
+	if d, err := y.TrySub(x); err == nil {
+		if q, err := d.TryDiv(x); err == nil {
+			if z, err := q.TryDiv(y); err == nil {
+				return z, nil
+			}
+		}
+	}
+	if z, err := x.Inv().TrySub(y.Inv()); err == nil {
+		return z, nil
+	}
+	r := new(big.Rat).Inv(x.BigRat())
+	return FromBigRat(r.Sub(r, new(big.Rat).Inv(y.BigRat())))
+}
+
+// RelativeDifference returns (x-y)/y, the signed relative error of x with
+// respect to the reference value y. It is positive if x is farther from zero
+// than y in the same direction, and negative if x is nearer to zero or on the
+// other side of it. The result is exact: RelativeDifference tries both
+// (x-y)/y and x/y-1, then falls back on big.Rat, and returns an error only if
+// the result does not fit. It returns ErrDivByZero if y is zero.
+func (x N) RelativeDifference(y N) (N, error) {
+	if y.IsZero() {
+		return N{}, ErrDivByZero
+	}
+	if d, err := x.TrySub(y); err == nil {
+		if z, err := d.TryDiv(y); err == nil {
+			return z, nil
+		}
+	}
+	if q, err := x.TryDiv(y); err == nil {
+		if z, err := q.TrySubInt64(1); err == nil {
+			return z, nil
+		}
+	}
+	yr := y.BigRat()
+	d := new(big.Rat).Sub(x.BigRat(), yr)
+	return FromBigRat(d.Quo(d, yr))
+}
+
+// SecantStep returns the next estimate of a root of f by the secant method,
+// given two previous estimates x and x1 and the values of f at them, fx0 and
+// fx1. This is where the line through (x, fx0) and (x1, fx1) crosses zero:
+//
+//	x1 - fx1*(x1-x)/(fx1-fx0)
+//
+// The result is exact: SecantStep falls back on big.Rat if an intermediate
+// value overflows, and returns an error only if the result does not fit. It
+// returns ErrDivByZero if fx0 == fx1.
+func (x N) SecantStep(fx0, x1, fx1 N) (N, error) {
+	if fx0 == fx1 {
+		return N{}, ErrDivByZero
+	}
+	if z, err := secantStep(x, fx0, x1, fx1); err == nil {
+		return z, nil
+	}
+	x1r, fx1r := x1.BigRat(), fx1.BigRat()
+	dx := new(big.Rat).Sub(x1r, x.BigRat())
+	df := new(big.Rat).Sub(fx1r, fx0.BigRat())
+	t := dx.Mul(dx, fx1r)
+	t.Quo(t, df)
+	return FromBigRat(t.Sub(x1r, t))
+}
+
+// secantStep is like SecantStep but fails if any intermediate value overflows.
+func secantStep(x0, fx0, x1, fx1 N) (N, error) {
+	dx, err := x1.TrySub(x0)
+	if err != nil {
+		return N{}, err
+	}
+	df, err := fx1.TrySub(fx0)
+	if err != nil {
+		return N{}, err
+	}
+	t, err := fx1.TryMul(dx)
+	if err != nil {
+		return N{}, err
+	}
+	if t, err = t.TryDiv(df); err != nil {
+		return N{}, err
+	}
+	return x1.TrySub(t)
+}
+
+// TryAddInt64 adds x and the integer k and returns the result.
+// TryAddInt64 returns 0 and a non-nil error if the result would overflow.
+func (x N) TryAddInt64(k int64) (N, error) {
+	// (m/n)+k = (m+k*n)/n, which is already reduced since any factor common
+	// to m+k*n and n would also have to divide m
+	m, ok := addMul64(x.Num(), k, x.Den())
+	if !ok {
+		return N{}, ErrNumOverflow
+	}
+	return tryAlreadyReduced(m, x.Den())
+}
+
+// AddInt64 is like TryAddInt64 but panics instead of returning an error.
+func (x N) AddInt64(k int64) N {
+	z, err := x.TryAddInt64(k)
+	if err != nil {
+		panic(err)
+	}
+	return z
+}
+
+// TrySubInt64 subtracts the integer k from x and returns the result.
+// TrySubInt64 returns 0 and a non-nil error if the result would overflow.
+func (x N) TrySubInt64(k int64) (N, error) {
+	// x-k = -(-x+k), which avoids negating k (it might be math.MinInt64)
+	z, err := x.Neg().TryAddInt64(k)
+	return z.Neg(), err
+}
+
+// SubInt64 is like TrySubInt64 but panics instead of returning an error.
+func (x N) SubInt64(k int64) N {
+	z, err := x.TrySubInt64(k)
+	if err != nil {
+		panic(err)
+	}
+	return z
+}
+
+// TryMulInt64 multiplies x by the integer k and returns the result.
+// TryMulInt64 returns 0 and a non-nil error if the result would overflow.
+func (x N) TryMulInt64(k int64) (N, error) {
+	if k == 0 || x.m == 0 {
+		return N{}, nil
+	}
+	sgn := sgn64(x.Num()) * sgn64(k)
+	// since x is reduced, only the factors common to k and the denominator
+	// need to be divided out; uint64 holds |k| even for math.MinInt64
+	mk, n := uint64(k), x.Den()
+	if k < 0 {
+		mk = -mk
+	}
+	d := GCD(int64(mk%uint64(n)), n)
+	mk, n = mk/uint64(d), n/d
+	hi, lo := bits.Mul64(uint64(abs64(x.Num())), mk)
+	if hi > 0 || lo > math.MaxInt64 {
+		return N{}, ErrNumOverflow
+	}
+	return tryAlreadyReduced(sgn*int64(lo), n)
+}
+
+// MulInt64 is like TryMulInt64 but panics instead of returning an error.
+func (x N) MulInt64(k int64) N {
+	z, err := x.TryMulInt64(k)
+	if err != nil {
+		panic(err)
+	}
+	return z
+}
+
+// TryDivInt64 divides x by the integer k and returns the result.
+// TryDivInt64 returns 0 and a non-nil error for division by zero or if the
+// result would overflow.
+func (x N) TryDivInt64(k int64) (N, error) {
+	if k == 0 {
+		return N{}, ErrDivByZero
+	} else if x.m == 0 {
+		return N{}, nil
+	}
+	sgn := sgn64(x.Num()) * sgn64(k)
+	// since x is reduced, only the factors common to k and the numerator
+	// need to be divided out; uint64 holds |k| even for math.MinInt64
+	m, nk := abs64(x.Num()), uint64(k)
+	if k < 0 {
+		nk = -nk
+	}
+	d := GCD(int64(nk%uint64(m)), m)
+	m, nk = m/d, nk/uint64(d)
+	hi, lo := bits.Mul64(uint64(x.Den()), nk)
+	if hi > 0 || lo > math.MaxInt64 {
+		return N{}, ErrDenOverflow
+	}
+	return tryAlreadyReduced(sgn*m, int64(lo))
+}
+
+// DivInt64 is like TryDivInt64 but panics instead of returning an error.
+func (x N) DivInt64(k int64) N {
+	z, err := x.TryDivInt64(k)
+	if err != nil {
+		panic(err)
+	}
+	return z
+}
+
 // RationalString returns a string representation of x, as m+sep+n.
 // For example, x.String() is equivalent to x.RationalString("/").
 func (x N) RationalString(sep string) string {
 	return fmt.Sprintf("%d%s%d", x.Num(), sep, x.Den())
 }
 
+// MixedString returns a string representation of x as a mixed number, as
+// "W m/n" where W is the integer part and m/n is the remaining fraction.
+// If either part is zero, it is omitted, so integers are formatted as "W" and
+// values between -1 and 1 as "m/n"; zero is "0". If x is negative, the
+// string begins with a negative sign, as in "-2 1/3".
+func (x N) MixedString() string {
+	m, n := x.Num(), x.Den()
+	w, r := m/n, abs64(m%n)
+	switch {
+	case r == 0:
+		return strconv.FormatInt(w, 10)
+	case w == 0:
+		return x.String()
+	}
+	return fmt.Sprintf("%d %d/%d", w, r, n)
+}
+
 // String returns a string representation of x, as m/n.
 func (x N) String() string {
 	return x.RationalString("/")
 }
 
+// CompactString returns a string representation of x, as m/n, or just m if
+// x is an integer (n == 1).
+func (x N) CompactString() string {
+	if x.n == 0 {
+		return strconv.FormatInt(x.m, 10)
+	}
+	return x.String()
+}
+
 // DecimalString returns a string representation of x, as a decimal number
 // to the given number of digits after the decimal point.
 // The last digit is rounded to nearest, with ties rounded away from zero.
@@ -579,25 +1529,7 @@ func (x N) DecimalString(prec int) string {
 			digits = append(digits, '0')
 			continue
 		}
-		// now we multiply the remainder by 10 to extract another decimal
-		// digit, then re-divide by 10 to get a new quotient and remainder for
-		// the next iteration
-		if r < math.MaxInt64/10 {
-			// use ordinary arithmetic if we can
-			r *= 10
-			q, r = r/n, r%n
-		} else {
-			// r is too large so we have to use wide arithmetic to avoid
-			// overflow; this gives us (rh:rl) <= MaxInt64*10, which is
-			// (4:18446744073709551606) according to big.Int
-			rh, rl := bits.Mul64(uint64(r), 10)
-			// we know that we got here because r >= MaxInt64/10 and moreover
-			// that r is a remainder of division by n, so n > r, thus
-			// n > MaxInt64/10 > rh and therefore Div64 won't panic
-			quo, rem := bits.Div64(rh, rl, uint64(n))
-			// quo < 10 and rem < n <= MaxInt64 so int64 cast is safe
-			q, r = int64(quo), int64(rem)
-		}
+		q, r = nextDecimalDigit(r, n)
 		digits = append(digits, byte(q)+'0')
 	}
 	// use digit in last position to round
@@ -634,6 +1566,224 @@ func (x N) DecimalString(prec int) string {
 	return buf.String()
 }
 
+// DecimalError returns the exact rounding error of x.DecimalString(prec),
+// which is x minus the value of that string. It is positive if the string
+// is less than x and negative if it is greater. DecimalError returns an
+// overflow error if the error does not fit, which can happen for large prec.
+func (x N) DecimalError(prec int) (N, error) {
+	if prec < 0 {
+		prec = 0
+	}
+	// DecimalString rounds half away from zero on the digit after the last,
+	// which is the same as rounding |x|*10^prec to the nearest integer with
+	// ties going up, so we build the rounded value directly from that
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(prec)), nil)
+	m, n := new(big.Int).Mul(big.NewInt(abs64(x.Num())), scale), big.NewInt(x.Den())
+	q, r := m.QuoRem(m, n, new(big.Int))
+	if r.Lsh(r, 1).Cmp(n) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if x.m < 0 {
+		q.Neg(q)
+	}
+	d := new(big.Rat).SetFrac(q, scale)
+	return FromBigRat(d.Sub(x.BigRat(), d))
+}
+
+// BaseString returns a string representation of x in the given base, with
+// prec digits after the radix point, the last of which is rounded according
+// to mode. Digits greater than 9 are written with lowercase letters, as by
+// strconv.FormatInt. If prec <= 0, the radix point is omitted from the string.
+// As with DecimalString, the string has a negative sign if x is negative,
+// even if the result of rounding is zero.
+// BaseString returns ErrInvalid if base is not in [2, 36].
+func (x N) BaseString(base, prec int, mode RoundingMode) (string, error) {
+	if base < 2 || base > 36 {
+		return "", ErrInvalid
+	}
+	if prec < 0 {
+		prec = 0
+	}
+	neg := x.m < 0
+	// scale |x| by base^prec and round it to an integer, whose digits are then
+	// the digits of the result with the radix point prec places from the end
+	b := big.NewInt(int64(base))
+	m, n := big.NewInt(abs64(x.Num())), big.NewInt(x.Den())
+	m.Mul(m, b.Exp(b, big.NewInt(int64(prec)), nil))
+	q, r := m.QuoRem(m, n, new(big.Int))
+	if r.Sign() != 0 {
+		half := r.Lsh(r, 1).Cmp(n)
+		if mode.roundsAway(neg, q.Bit(0) != 0, half) {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	digits := q.Text(base)
+	if len(digits) <= prec {
+		digits = strings.Repeat("0", prec-len(digits)+1) + digits
+	}
+	var buf strings.Builder
+	if neg {
+		buf.WriteByte('-')
+	}
+	k := len(digits) - prec
+	buf.WriteString(digits[:k])
+	if prec > 0 {
+		buf.WriteByte('.')
+		buf.WriteString(digits[k:])
+	}
+	return buf.String(), nil
+}
+
+// ExactString returns the exact decimal representation of x and true if x
+// has a terminating decimal expansion, which is when its denominator has no
+// prime factors other than 2 and 5. Otherwise, it returns x as m/n and false.
+// Integers have no decimal point, and no trailing zeros are ever written.
+// In the decimal case, the string is the same as that returned by DecimalString
+// and big.Rat.FloatString with just enough digits after the decimal point.
+func (x N) ExactString() (string, bool) {
+	n := uint64(x.Den())
+	twos := bits.TrailingZeros64(n)
+	n >>= twos
+	fives := 0
+	for n%5 == 0 {
+		n /= 5
+		fives++
+	}
+	if n != 1 {
+		return x.String(), false
+	}
+	return x.DecimalString(max(twos, fives)), true
+}
+
+// TerminatesInBase returns true if x has a terminating positional expansion
+// in the given base, which is when every prime factor of its denominator
+// also divides the base. TerminatesInBase returns ErrInvalid if base < 2.
+func (x N) TerminatesInBase(base int) (bool, error) {
+	if base < 2 {
+		return false, ErrInvalid
+	}
+	d := x.Den()
+	// divide out the factors d shares with base until there are none left
+	for g := GCD(d, int64(base)); g > 1; g = GCD(d, int64(base)) {
+		d /= g
+	}
+	return d == 1, nil
+}
+
+// PadDecimalString is like DecimalString but pads the result on the left
+// with spaces to be at least width characters long. Values formatted with
+// the same prec and width are right-aligned, so their decimal points line up.
+// If the result is already width characters or longer, it is not padded.
+func (x N) PadDecimalString(prec, width int) string {
+	s := x.DecimalString(prec)
+	if len(s) >= width {
+		return s
+	}
+	return strings.Repeat(" ", width-len(s)) + s
+}
+
+// DecimalStringPadded is like DecimalString but pads the integer part of the
+// result on the left with zeroes to be at least intDigits digits long. The
+// negative sign, if any, comes before the padding, so for example -7.5 with
+// intDigits == 3 and prec == 2 is "-007.50". If the integer part already has
+// intDigits or more digits, it is not padded.
+func (x N) DecimalStringPadded(intDigits, prec int) string {
+	s := x.DecimalString(prec)
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	n := strings.IndexByte(s, '.')
+	if n < 0 {
+		n = len(s)
+	}
+	if n >= intDigits {
+		return sign + s
+	}
+	return sign + strings.Repeat("0", intDigits-n) + s
+}
+
+// ScientificString returns a string representation of x in scientific
+// notation, as a mantissa with one nonzero digit before the decimal point
+// and prec digits after it, followed by "e" and a base-10 exponent. For
+// example, 12345 is "1.23e4" for prec == 2. The last digit of the mantissa is
+// rounded according to mode; if rounding carries into a new leading digit,
+// the exponent is incremented instead, so the mantissa is always in [1, 10).
+// If prec <= 0, the decimal point is omitted from the string.
+// If x is zero, the string is "0".
+func (x N) ScientificString(prec int, mode RoundingMode) string {
+	if prec < 0 {
+		prec = 0
+	}
+	if x.m == 0 {
+		return "0"
+	}
+	neg := x.m < 0
+	e := orderOfMagnitude(abs64(x.Num()), x.Den())
+	// scale x by 10^(prec-e) so that its integer part has prec+1 digits, then
+	// round it to an integer; this is done with big.Int because the scaled
+	// value might not fit in 64 bits
+	m, n := big.NewInt(abs64(x.Num())), big.NewInt(x.Den())
+	if k := prec - e; k >= 0 {
+		m.Mul(m, pow10Big(k))
+	} else {
+		n.Mul(n, pow10Big(-k))
+	}
+	q, r := m.QuoRem(m, n, new(big.Int))
+	if r.Sign() != 0 {
+		half := r.Lsh(r, 1).Cmp(n)
+		if mode.roundsAway(neg, q.Bit(0) != 0, half) {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	digits := q.String()
+	if len(digits) > prec+1 {
+		// rounding carried over (e.g. 9.99 became 10.00), so the digits are a
+		// 1 followed by zeroes and we just drop the last one
+		digits = digits[:prec+1]
+		e++
+	}
+	var buf strings.Builder
+	if neg {
+		buf.WriteByte('-')
+	}
+	buf.WriteString(digits[:1])
+	if prec > 0 {
+		buf.WriteByte('.')
+		buf.WriteString(digits[1:])
+	}
+	buf.WriteByte('e')
+	buf.WriteString(strconv.Itoa(e))
+	return buf.String()
+}
+
+// DigitStream produces the decimal digits of the fractional part of a
+// rational number one at a time. See N.DigitStream for details.
+type DigitStream struct {
+	r, n int64
+}
+
+// DigitStream returns a stream of the decimal digits of the fractional part
+// of |x|, computed by long division. For example, the digits of 1/7 are
+// '1', '4', '2', '8', '5', '7', '1', and so on.
+func (x N) DigitStream() *DigitStream {
+	n := x.Den()
+	return &DigitStream{abs64(x.Num() % n), n}
+}
+
+// Next returns the next digit, as an ASCII character from '0' to '9', and
+// true, or else returns 0 and false if the remaining digits are all zero.
+// A terminating expansion thus ends after its last nonzero digit, while a
+// repeating expansion never ends.
+func (s *DigitStream) Next() (byte, bool) {
+	if s.r == 0 {
+		return 0, false
+	}
+	var q int64
+	q, s.r = nextDecimalDigit(s.r, s.n)
+	return byte(q) + '0', true
+}
+
 // Float64 returns the floating-point equivalent of x. If exact is true, then
 // v is exactly equal to x; otherwise, it is the closest approximation.
 func (x N) Float64() (v float64, exact bool) {
@@ -644,16 +1794,106 @@ func (x N) Float64() (v float64, exact bool) {
 		return 0, true
 	}
 
-	// integers are exact as long as they fit in the mantissa
+	// integers are exact as long as their significant bits (excluding
+	// trailing zeroes) fit in the mantissa
 	prec := bits.Len64(uint64(abs64(m)))
 	if n == 1 {
-		return float64(m), prec <= 53
+		return float64(m), prec-bits.TrailingZeros64(uint64(m)) <= 53
 	}
 
 	// non-integers are exact as long as the numerator fits in the mantissa
 	// and the denominator is a power of two
-	nIsPow2 := bits.OnesCount64(uint64(n)) == 1
-	return float64(m) / float64(n), prec <= 53 && nIsPow2
+	return float64(m) / float64(n), prec <= 53 && x.IsDyadic()
+}
+
+// AddIsFloatExact returns true if x+y is exactly representable as a float64.
+// If x+y overflows N, its exact value is still checked using big.Rat.
+func (x N) AddIsFloatExact(y N) bool {
+	if z, err := x.TryAdd(y); err == nil {
+		_, exact := z.Float64()
+		return exact
+	}
+	_, exact := new(big.Rat).Add(x.BigRat(), y.BigRat()).Float64()
+	return exact
+}
+
+// MulIsFloatExact returns true if x*y is exactly representable as a float64.
+// If x*y overflows N, its exact value is still checked using big.Rat.
+func (x N) MulIsFloatExact(y N) bool {
+	if z, err := x.TryMul(y); err == nil {
+		_, exact := z.Float64()
+		return exact
+	}
+	_, exact := new(big.Rat).Mul(x.BigRat(), y.BigRat()).Float64()
+	return exact
+}
+
+// Float64ULPError returns the float64 approximation of x given by Float64,
+// along with the distance between it and the exact value of x measured in
+// units in the last place (ULPs) of the approximation and rounded up.
+// Thus, ulps is 0 if and only if v is exact, and 1 if v is inexact but
+// within one ULP of x.
+func (x N) Float64ULPError() (v float64, ulps int) {
+	v, exact := x.Float64()
+	if exact {
+		return v, 0
+	}
+	a := math.Abs(v)
+	ulp := new(big.Rat).SetFloat64(math.Nextafter(a, math.Inf(1)) - a)
+	d := new(big.Rat).SetFloat64(v)
+	d.Sub(x.BigRat(), d)
+	d.Abs(d)
+	d.Quo(d, ulp)
+	q, r := new(big.Int).QuoRem(d.Num(), d.Denom(), new(big.Int))
+	if r.Sign() != 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return v, int(q.Int64())
+}
+
+// Float32 returns the nearest float32 to x, with ties to even. If exact is
+// true, then v is exactly equal to x. Since every valid N has a magnitude
+// between 2^-63 and 2^63, the result is never subnormal or infinite.
+func (x N) Float32() (v float32, exact bool) {
+	m, n := x.Num(), x.Den()
+
+	// check for zero, trivial case
+	if m == 0 {
+		return 0, true
+	}
+
+	// if both m and n fit in the mantissa, then they convert exactly, and
+	// since IEEE-754 division is correctly rounded, so is the result; it is
+	// exact if and only if the denominator is a power of two
+	if abs64(m) < 1<<24 && n < 1<<24 {
+		return float32(m) / float32(n), x.IsDyadic()
+	}
+
+	// otherwise, dividing would round twice, so let big.Rat do it right
+	return x.BigRat().Float32()
+}
+
+// IsDyadic returns true if the denominator of x is a power of two.
+func (x N) IsDyadic() bool {
+	return bits.OnesCount64(uint64(x.Den())) == 1
+}
+
+// DyadicExponent returns the base-2 logarithm of the denominator of x, if x
+// is dyadic; otherwise, it returns -1.
+func (x N) DyadicExponent() int {
+	if !x.IsDyadic() {
+		return -1
+	}
+	return bits.TrailingZeros64(uint64(x.Den()))
+}
+
+// DyadicString returns a string representation of x, as m/n, if x is dyadic.
+// Otherwise, it returns an empty string and ErrInexact.
+func (x N) DyadicString() (string, error) {
+	if !x.IsDyadic() {
+		return "", ErrInexact
+	}
+	return x.String(), nil
 }
 
 // BigRat converts x to a new big.Rat.
@@ -666,6 +1906,14 @@ func (x N) reduce() (N, error) {
 	if x.m == 0 {
 		return N{}, nil
 	}
+	if x.m == math.MinInt64 {
+		// abs64 can't handle this numerator, but its only prime factor is 2,
+		// so either we can divide out a 2 or it can't be reduced at all
+		if x.Den()%2 != 0 {
+			return N{}, ErrNumOverflow
+		}
+		return N{x.m / 2, x.Den()/2 - 1}.reduce()
+	}
 	sgn := int64(x.Sign())
 	m, n := abs64(x.Num()), x.Den()
 	d := GCD(m, n)
@@ -677,6 +1925,51 @@ func (x N) reduce() (N, error) {
 	return N{num, den}, nil
 }
 
+// nextDecimalDigit performs one step of long division, returning the next
+// decimal digit q of r/n and the new remainder, for 0 <= r < n.
+func nextDecimalDigit(r, n int64) (q, rem int64) {
+	// we multiply the remainder by 10 to extract another decimal digit, then
+	// re-divide by n to get a new quotient and remainder for the next step
+	if r < math.MaxInt64/10 {
+		// use ordinary arithmetic if we can
+		r *= 10
+		return r / n, r % n
+	}
+	// r is too large so we have to use wide arithmetic to avoid overflow;
+	// this gives us (rh:rl) <= MaxInt64*10, which is
+	// (4:18446744073709551606) according to big.Int
+	rh, rl := bits.Mul64(uint64(r), 10)
+	// we know that we got here because r >= MaxInt64/10 and moreover that r
+	// is a remainder of division by n, so n > r, thus n > MaxInt64/10 > rh
+	// and therefore Div64 won't panic
+	quo, re := bits.Div64(rh, rl, uint64(n))
+	// quo < 10 and re < n <= MaxInt64 so int64 cast is safe
+	return int64(quo), int64(re)
+}
+
+// orderOfMagnitude returns floor(log10(m/n)) for positive m and n.
+func orderOfMagnitude(m, n int64) int {
+	// the number of digits in m minus the number of digits in n is either
+	// the answer or one more than it, so we check which by comparing m/n
+	// with 10^e
+	e := len(strconv.FormatInt(m, 10)) - len(strconv.FormatInt(n, 10))
+	bm, bn := big.NewInt(m), big.NewInt(n)
+	if e >= 0 {
+		bn.Mul(bn, pow10Big(e))
+	} else {
+		bm.Mul(bm, pow10Big(-e))
+	}
+	if bm.Cmp(bn) < 0 {
+		e--
+	}
+	return e
+}
+
+// pow10Big returns 10^k as a new big.Int, for k >= 0.
+func pow10Big(k int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(k)), nil)
+}
+
 // abs64 returns the absolute value of x.
 // WARNING: abs64(math.MinInt64) == math.MinInt64 < 0.
 func abs64(x int64) int64 {
@@ -686,6 +1979,110 @@ func abs64(x int64) int64 {
 	return x
 }
 
+// addMul64 returns m+k*n and true if the result fits in int64, or else 0 and
+// false. The intermediate product is computed with 128-bit precision, so it
+// may overflow as long as the final result doesn't.
+func addMul64(m, k, n int64) (int64, bool) {
+	// compute |k|*|n| and then negate it in two's complement if needed;
+	// uint64 conversion gives the right magnitude even for math.MinInt64
+	ak, an := uint64(k), uint64(n)
+	if k < 0 {
+		ak = -ak
+	}
+	if n < 0 {
+		an = -an
+	}
+	hi, lo := bits.Mul64(ak, an)
+	if (k < 0) != (n < 0) {
+		var b uint64
+		lo, b = bits.Sub64(0, lo, 0)
+		hi, _ = bits.Sub64(0, hi, b)
+	}
+	// add m, sign-extended to 128 bits
+	var c uint64
+	lo, c = bits.Add64(lo, uint64(m), 0)
+	hi, _ = bits.Add64(hi, uint64(m>>63), c)
+	// the result fits if the high bits are just the sign extension of lo
+	if hi != uint64(int64(lo)>>63) {
+		return 0, false
+	}
+	return int64(lo), true
+}
+
+// cmp128 compares the unsigned 128-bit integers (h1:l1) and (h2:l2) and
+// returns -1, 0, or 1 in the manner of N.Cmp.
+func cmp128(h1, l1, h2, l2 uint64) int {
+	switch {
+	case h1 < h2:
+		return -1
+	case h1 > h2:
+		return 1
+	case l1 < l2:
+		return -1
+	case l1 > l2:
+		return 1
+	}
+	return 0
+}
+
+// isqrt64 returns the square root of v and true if v is a perfect square,
+// or else 0 and false. The argument must be non-negative.
+func isqrt64(v int64) (int64, bool) {
+	// math.Sqrt is correctly rounded, but float64(v) may not be, so the
+	// estimate may be off by one in either direction
+	r := int64(math.Sqrt(float64(v)))
+	for r > 0 && (r > math.MaxInt64/r || r*r > v) {
+		r--
+	}
+	for (r+1) <= math.MaxInt64/(r+1) && (r+1)*(r+1) <= v {
+		r++
+	}
+	if r*r != v {
+		return 0, false
+	}
+	return r, true
+}
+
+// iroot64 returns the k-th root of v and true if v is a perfect k-th power,
+// or else 0 and false. The arguments must be non-negative and positive,
+// respectively.
+func iroot64(v, k int64) (int64, bool) {
+	if k == 1 || v <= 1 {
+		return v, true
+	} else if k >= 63 {
+		// 1 < v < 2^63, so the root is strictly between 1 and 2
+		return 0, false
+	}
+	// the root is less than 2^32, so the estimate is off by at most one
+	r := int64(math.Round(math.Pow(float64(v), 1/float64(k))))
+	for c := max(r-1, 0); c <= r+1; c++ {
+		if z, ok := pow64(c, k); ok && z == v {
+			return c, true
+		}
+	}
+	return 0, false
+}
+
+// pow64 returns b raised to the power e and true, or else 0 and false if the
+// result overflows int64. The arguments must be non-negative.
+func pow64(b, e int64) (int64, bool) {
+	if b <= 1 {
+		if b == 0 && e > 0 {
+			return 0, true
+		}
+		return 1, true
+	}
+	// b >= 2, so this loop overflows after at most 63 iterations
+	z := int64(1)
+	for ; e > 0; e-- {
+		if z > math.MaxInt64/b {
+			return 0, false
+		}
+		z *= b
+	}
+	return z, true
+}
+
 // sgn64 returns -1 if x < 0, 0 if x == 0, and 1 if x > 0.
 func sgn64(x int64) int64 {
 	if x == 0 {
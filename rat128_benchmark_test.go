@@ -37,6 +37,28 @@ func BenchmarkRat128_Mul(b *testing.B) {
 	}
 }
 
+func BenchmarkRat128_TrySqr(b *testing.B) {
+	for name, c := range BenchCases {
+		x := c.X
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				x.TrySqr()
+			}
+		})
+	}
+}
+
+func BenchmarkRat128_TryMulSelf(b *testing.B) {
+	for name, c := range BenchCases {
+		x := c.X
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				x.TryMul(x)
+			}
+		})
+	}
+}
+
 func BenchmarkBigRat_Add(b *testing.B) {
 	z := new(big.Rat)
 	for name, c := range BenchCases {
@@ -60,3 +82,35 @@ func BenchmarkBigRat_Mul(b *testing.B) {
 		})
 	}
 }
+
+// sumBenchXs has partial sums that fit, so the chained TryAdd baseline never
+// overflows and the comparison measures only the fast path overhead.
+var sumBenchXs = func() []rat128.N {
+	xs := make([]rat128.N, 1000)
+	for i := range xs {
+		xs[i] = New(int64(i%7+1), int64(i%5+2))
+	}
+	return xs
+}()
+
+func BenchmarkRat128_SumExact(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		rat128.SumExact(sumBenchXs)
+	}
+}
+
+func BenchmarkRat128_SumTryAdd(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		var sum rat128.N
+		for _, x := range sumBenchXs {
+			sum, _ = sum.TryAdd(x)
+		}
+	}
+}
+
+func BenchmarkRat128_SumExactBig(b *testing.B) {
+	xs := []rat128.N{New(1, P1*P2), New(1, P3*P4), New(-1, P1*P2), New(-1, P3*P4)}
+	for i := 0; i < b.N; i++ {
+		rat128.SumExact(xs)
+	}
+}
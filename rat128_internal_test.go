@@ -0,0 +1,39 @@
+package rat128
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestCheckInvariants(t *testing.T) {
+	cases := []struct {
+		X   N
+		Err error
+	}{
+		{N{}, nil},
+		{N{1, 1}, nil},
+		{N{-3, 3}, nil},
+		{N{math.MaxInt64, 0}, nil},
+		{N{-(math.MaxInt64 - 1), math.MaxInt64 - 1}, nil},
+		{N{1, -1}, ErrDenInvalid},
+		{N{1, -2}, ErrDenInvalid},
+		{N{1, math.MaxInt64}, ErrDenInvalid},
+		{N{math.MinInt64, 0}, ErrNumOverflow},
+		{N{2, 1}, ErrNotReduced},
+		{N{-6, 8}, ErrNotReduced},
+		{N{0, 1}, ErrNotReduced},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("{%d,%d}", c.X.m, c.X.n), func(t *testing.T) {
+			err := CheckInvariants(c.X)
+			if !errors.Is(err, c.Err) || (err == nil) != (c.Err == nil) {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			}
+			if valid := c.X.IsValid(); valid != (c.Err == nil) {
+				t.Errorf("got IsValid()=%v, want %v", valid, c.Err == nil)
+			}
+		})
+	}
+}
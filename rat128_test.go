@@ -1,8 +1,11 @@
 package rat128_test
 
 import (
+	"errors"
 	"fmt"
 	"math"
+	"math/big"
+	"strings"
 	"testing"
 
 	"github.com/kbolino/rat128"
@@ -20,6 +23,59 @@ const (
 var New = rat128.New
 var Zero rat128.N
 
+func TestTry(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		Num, Den int64
+		Rat      rat128.N
+		Err      error
+	}{
+		{0, 5, New(0, 1), nil},
+		{2, 4, New(1, 2), nil},
+		{-6, 4, New(-3, 2), nil},
+		{M, M, New(1, 1), nil},
+		{math.MinInt64, 2, New(-1<<62, 1), nil},
+		{math.MinInt64, 4, New(-1<<61, 1), nil},
+		{math.MinInt64, 6, New(-1<<62, 3), nil},
+		{math.MinInt64, 1 << 62, New(-2, 1), nil},
+		{math.MinInt64, 3, Zero, rat128.ErrNumOverflow},
+		{math.MinInt64, 1, Zero, rat128.ErrNumOverflow},
+		{1, 0, Zero, rat128.ErrDenInvalid},
+		{1, -2, Zero, rat128.ErrDenInvalid},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%d/%d", c.Num, c.Den), func(t *testing.T) {
+			x, err := rat128.Try(c.Num, c.Den)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if x != c.Rat {
+				t.Errorf("got %v, want %v", x, c.Rat)
+			} else if err == nil && x.Num() != c.Rat.Num() {
+				t.Errorf("got numerator %d, want %d", x.Num(), c.Rat.Num())
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	if x := New(math.MinInt64, 2); x.Num() != -1<<62 || x.Den() != 1 {
+		t.Errorf("got %v, want -4611686018427387904/1", x)
+	}
+	if x := New(math.MinInt64, 4); x.Num() != -1<<61 || x.Den() != 1 {
+		t.Errorf("got %v, want -2305843009213693952/1", x)
+	}
+	for _, den := range []int64{1, 3} {
+		func() {
+			defer func() {
+				if r := recover(); r != rat128.ErrNumOverflow {
+					t.Errorf("New(math.MinInt64, %d): got panic %v, want %v", den, r, rat128.ErrNumOverflow)
+				}
+			}()
+			New(math.MinInt64, den)
+		}()
+	}
+}
+
 func TestN_TryAdd(t *testing.T) {
 	cases := []struct {
 		X, Y, Z rat128.N
@@ -129,6 +185,39 @@ func TestN_TryMul(t *testing.T) {
 	}
 }
 
+func TestN_MulHeadroom(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		X, Y rat128.N
+		Bits int
+		OK   bool
+	}{
+		{New(1, 1), New(1, 1), 62, true},
+		{Zero, New(M, 1), 62, true},
+		{New(1, 2), New(-1, 2), 60, true},
+		{New(P1, P2*P3), New(P2, P1*P3), 29, true},
+		{New(1<<31, 3), New(9, 1<<31), 61, true},
+		{New(1<<31, 1), New(1<<31, 1), 0, true},
+		{New(1<<32, 1), New(-1<<31, 1), -1, false},
+		{New(M, 1), New(1, 1), 0, true},
+		{New(M, 1), New(1, M), 62, true},
+		{New(M, 1), New(M, 1), -63, false},
+		{New(1, M), New(-1, M), -63, false},
+		{New(P1*P2*P3, P4), New(P2*P3*P4, P1), -4, false},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s)*(%s)", c.X.RationalString("_"), c.Y.RationalString("_")), func(t *testing.T) {
+			bits, ok := c.X.MulHeadroom(c.Y)
+			if bits != c.Bits || ok != c.OK {
+				t.Errorf("got (%d, %t), want (%d, %t)", bits, ok, c.Bits, c.OK)
+			}
+			if _, err := c.X.TryMul(c.Y); (err == nil) != ok {
+				t.Errorf("TryMul returned error %v but ok is %t", err, ok)
+			}
+		})
+	}
+}
+
 func TestN_TryInv(t *testing.T) {
 	cases := []struct {
 		X, Z rat128.N
@@ -209,6 +298,9 @@ func TestN_Float64(t *testing.T) {
 		{New(-2, 3), -0.666_666_666_666_666_666, false},
 		{New(1, 7), 0.142_857_142_857_142_857, false},
 		{New(1<<63-1, 1), 9.223_372_036_854_775_807e18, false},
+		{New(1<<60, 1), 0x1p60, true},
+		{New(-0x1F<<55, 1), -0x1Fp55, true},
+		{New(1<<60+1, 1), 0x1p60, false},
 	}
 	for _, c := range cases {
 		t.Run(c.Rat.String(), func(t *testing.T) {
@@ -378,3 +470,1468 @@ func TestN_DecimalString(t *testing.T) {
 		})
 	}
 }
+
+func TestN_Cmp(t *testing.T) {
+	cases := []struct {
+		X, Y rat128.N
+		C    int
+	}{
+		{New(0, 1), New(0, 1), 0},
+		{New(1, 1), New(1, 1), 0},
+		{New(1, 2), New(1, 3), 1},
+		{New(1, 3), New(1, 2), -1},
+		{New(-1, 2), New(-1, 3), -1},
+		{New(-1, 3), New(-1, 2), 1},
+		{New(-1, 2), New(1, 3), -1},
+		{New(0, 1), New(-1, math.MaxInt64), 1},
+		{New(math.MaxInt64, 1), New(-math.MaxInt64, 1), 1},
+		{New(-math.MaxInt64, 1), New(math.MaxInt64, 1), -1},
+		{New(math.MaxInt64, 1), New(math.MaxInt64-1, 1), 1},
+		{New(1, math.MaxInt64), New(1, math.MaxInt64-1), -1},
+		{New(math.MaxInt64-1, math.MaxInt64), New(math.MaxInt64-2, math.MaxInt64-1), 1},
+		{New(-(math.MaxInt64 - 1), math.MaxInt64), New(-(math.MaxInt64 - 2), math.MaxInt64-1), -1},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s)<=>(%s)", c.X.RationalString("_"), c.Y.RationalString("_")), func(t *testing.T) {
+			if r := c.X.Cmp(c.Y); r != c.C {
+				t.Errorf("got %d, want %d", r, c.C)
+			}
+			ops := []struct {
+				Name      string
+				Got, Want bool
+			}{
+				{"Eq", c.X.Eq(c.Y), c.C == 0},
+				{"Lt", c.X.Lt(c.Y), c.C < 0},
+				{"Lte", c.X.Lte(c.Y), c.C <= 0},
+				{"Gt", c.X.Gt(c.Y), c.C > 0},
+				{"Gte", c.X.Gte(c.Y), c.C >= 0},
+			}
+			for _, op := range ops {
+				if op.Got != op.Want {
+					t.Errorf("got %s()=%v, want %v", op.Name, op.Got, op.Want)
+				}
+			}
+		})
+	}
+}
+
+func TestN_Between(t *testing.T) {
+	cases := []struct {
+		X, Lo, Hi rat128.N
+		Inclusive bool
+		Result    bool
+	}{
+		{New(1, 2), New(0, 1), New(1, 1), false, true},
+		{New(1, 2), New(0, 1), New(1, 1), true, true},
+		{New(0, 1), New(0, 1), New(1, 1), false, false},
+		{New(0, 1), New(0, 1), New(1, 1), true, true},
+		{New(1, 1), New(0, 1), New(1, 1), false, false},
+		{New(1, 1), New(0, 1), New(1, 1), true, true},
+		{New(2, 1), New(0, 1), New(1, 1), true, false},
+		{New(-1, 2), New(0, 1), New(1, 1), true, false},
+		{New(1, 2), New(1, 1), New(0, 1), true, false},
+		{New(1, 2), New(1, 2), New(1, 2), true, true},
+		{New(1, 2), New(1, 2), New(1, 2), false, false},
+		{New(0, 1), New(-math.MaxInt64, 1), New(math.MaxInt64, 1), false, true},
+		{New(math.MaxInt64, 1), New(-math.MaxInt64, 1), New(math.MaxInt64, 1), true, true},
+	}
+	for _, c := range cases {
+		name := fmt.Sprintf("(%s)in(%s,%s):%v", c.X.RationalString("_"), c.Lo.RationalString("_"), c.Hi.RationalString("_"), c.Inclusive)
+		t.Run(name, func(t *testing.T) {
+			if r := c.X.Between(c.Lo, c.Hi, c.Inclusive); r != c.Result {
+				t.Errorf("got %v, want %v", r, c.Result)
+			}
+		})
+	}
+}
+
+func TestN_IsDyadic(t *testing.T) {
+	cases := []struct {
+		Rat      rat128.N
+		IsDyadic bool
+		Exponent int
+		String   string
+	}{
+		{New(0, 1), true, 0, "0/1"},
+		{New(3, 1), true, 0, "3/1"},
+		{New(1, 2), true, 1, "1/2"},
+		{New(-1, 4), true, 2, "-1/4"},
+		{New(3, 8), true, 3, "3/8"},
+		{New(1, 1<<62), true, 62, "1/4611686018427387904"},
+		{New(1, 3), false, -1, ""},
+		{New(1, 6), false, -1, ""},
+		{New(1, math.MaxInt64), false, -1, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.Rat.String(), func(t *testing.T) {
+			if r := c.Rat.IsDyadic(); r != c.IsDyadic {
+				t.Errorf("got IsDyadic()=%v, want %v", r, c.IsDyadic)
+			}
+			if e := c.Rat.DyadicExponent(); e != c.Exponent {
+				t.Errorf("got DyadicExponent()=%d, want %d", e, c.Exponent)
+			}
+			s, err := c.Rat.DyadicString()
+			if c.IsDyadic && err != nil {
+				t.Errorf("got unexpected error %v", err)
+			} else if !c.IsDyadic && err != rat128.ErrInexact {
+				t.Errorf("got error %v, want %v", err, rat128.ErrInexact)
+			} else if s != c.String {
+				t.Errorf("got DyadicString()=%q, want %q", s, c.String)
+			}
+		})
+	}
+}
+
+func TestN_TrySqr(t *testing.T) {
+	cases := []rat128.N{
+		New(0, 1),
+		New(1, 1),
+		New(-1, 1),
+		New(2, 3),
+		New(-2, 3),
+		New(P1, P2),
+		New(-P1*P2, P3),
+		New(math.MaxInt32, 1),
+		New(1, math.MaxInt32),
+		New(3037000499, 3037000498),
+		New(3037000500, 1),
+		New(1, 3037000500),
+		New(math.MaxInt64, 1),
+		New(1, math.MaxInt64),
+	}
+	for _, x := range cases {
+		t.Run(fmt.Sprintf("(%s)", x.RationalString("_")), func(t *testing.T) {
+			z, err := x.TrySqr()
+			wantZ, wantErr := x.TryMul(x)
+			if err != wantErr {
+				t.Errorf("got error %v, want %v", err, wantErr)
+			} else if z != wantZ {
+				t.Errorf("got %v, want %v", z, wantZ)
+			}
+		})
+	}
+}
+
+func TestN_PadDecimalString(t *testing.T) {
+	cases := []struct {
+		Rat    rat128.N
+		Prec   int
+		Width  int
+		String string
+	}{
+		{New(1, 2), 2, 0, "0.50"},
+		{New(1, 2), 2, 4, "0.50"},
+		{New(1, 2), 2, 6, "  0.50"},
+		{New(-1, 2), 2, 6, " -0.50"},
+		{New(100, 3), 2, 6, " 33.33"},
+		{New(-100, 3), 2, 6, "-33.33"},
+		{New(-1000, 3), 2, 6, "-333.33"},
+		{New(7, 1), 0, 3, "  7"},
+	}
+	for _, c := range cases {
+		r := c.Rat
+		t.Run(fmt.Sprintf("(%s):%d:%d", r, c.Prec, c.Width), func(t *testing.T) {
+			s := r.PadDecimalString(c.Prec, c.Width)
+			if s != c.String {
+				t.Errorf("got %q, want %q", s, c.String)
+			}
+		})
+	}
+}
+
+func TestN_ClampUnit(t *testing.T) {
+	cases := []struct {
+		X, Z rat128.N
+	}{
+		{New(0, 1), New(0, 1)},
+		{New(1, 1), New(1, 1)},
+		{New(1, 2), New(1, 2)},
+		{New(-1, 2), New(0, 1)},
+		{New(3, 2), New(1, 1)},
+		{New(2, 1), New(1, 1)},
+		{New(math.MaxInt64-1, math.MaxInt64), New(math.MaxInt64-1, math.MaxInt64)},
+		{New(math.MaxInt64, math.MaxInt64-1), New(1, 1)},
+		{New(-math.MaxInt64, 1), New(0, 1)},
+		{New(-1, math.MaxInt64), New(0, 1)},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s)", c.X.RationalString("_")), func(t *testing.T) {
+			z := c.X.ClampUnit()
+			if z != c.Z {
+				t.Errorf("got %v, want %v", z, c.Z)
+			}
+			if p := c.X.IsProbability(); p != (z == c.X) {
+				t.Errorf("got IsProbability()=%v, want %v", p, z == c.X)
+			}
+		})
+	}
+}
+
+func TestN_ProbabilityFromOdds(t *testing.T) {
+	cases := []struct {
+		Odds, Prob rat128.N
+		Err        error
+	}{
+		{New(0, 1), New(1, 1), nil},
+		{New(1, 1), New(1, 2), nil},
+		{New(3, 2), New(2, 5), nil},
+		{New(2, 3), New(3, 5), nil},
+		{New(99, 1), New(1, 100), nil},
+		{New(1, 99), New(99, 100), nil},
+		{New(math.MaxInt64-1, 1), New(1, math.MaxInt64), nil},
+		{New(-1, 1), Zero, rat128.ErrDivByZero},
+		{New(math.MaxInt64, 1), Zero, rat128.ErrNumOverflow},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s)", c.Odds.RationalString("_")), func(t *testing.T) {
+			p, err := c.Odds.ProbabilityFromOdds()
+			if err != c.Err {
+				t.Fatalf("got error %v, want %v", err, c.Err)
+			} else if c.Err != nil {
+				return
+			}
+			if p != c.Prob {
+				t.Errorf("got %v, want %v", p, c.Prob)
+			}
+			odds, err := p.OddsFromProbability()
+			if err != nil {
+				t.Fatalf("got unexpected error %v", err)
+			} else if odds != c.Odds {
+				t.Errorf("got %v, want %v", odds, c.Odds)
+			}
+		})
+	}
+}
+
+func TestN_OddsFromProbability(t *testing.T) {
+	_, err := New(0, 1).OddsFromProbability()
+	if err != rat128.ErrDivByZero {
+		t.Errorf("got error %v, want %v", err, rat128.ErrDivByZero)
+	}
+}
+
+func TestParseRationalFields(t *testing.T) {
+	cases := []struct {
+		Line, Sep string
+		Rats      []rat128.N
+		Err       error
+	}{
+		{"", "", []rat128.N{}, nil},
+		{"1/2", ",", []rat128.N{New(1, 2)}, nil},
+		{"1/2, 0.25 ,-3", ",", []rat128.N{New(1, 2), New(1, 4), New(-3, 1)}, nil},
+		{"  1/2\t0.25   -3 ", "", []rat128.N{New(1, 2), New(1, 4), New(-3, 1)}, nil},
+		{"1/2;;3", ";", nil, rat128.ErrFmtInvalid},
+		{"1/2,1/0", ",", nil, rat128.ErrDenInvalid},
+		{"1/2,x", ",", nil, rat128.ErrFmtInvalid},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%q", c.Line), func(t *testing.T) {
+			rats, err := rat128.ParseRationalFields(c.Line, c.Sep)
+			if !errors.Is(err, c.Err) {
+				t.Fatalf("got error %v, want %v", err, c.Err)
+			}
+			if len(rats) != len(c.Rats) {
+				t.Fatalf("got %v, want %v", rats, c.Rats)
+			}
+			for i := range rats {
+				if rats[i] != c.Rats[i] {
+					t.Errorf("got %v, want %v", rats, c.Rats)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestN_ScientificString(t *testing.T) {
+	cases := []struct {
+		Rat    rat128.N
+		Prec   int
+		Mode   rat128.RoundingMode
+		String string
+	}{
+		{New(0, 1), 2, rat128.RoundHalfAwayFromZero, "0"},
+		{New(1, 1), 0, rat128.RoundHalfAwayFromZero, "1e0"},
+		{New(1, 1), 2, rat128.RoundHalfAwayFromZero, "1.00e0"},
+		{New(12345, 1), 2, rat128.RoundHalfAwayFromZero, "1.23e4"},
+		{New(-12345, 1), 2, rat128.RoundHalfAwayFromZero, "-1.23e4"},
+		{New(12345, 1), 3, rat128.RoundHalfAwayFromZero, "1.235e4"},
+		{New(12345, 1), 3, rat128.RoundHalfToEven, "1.234e4"},
+		{New(12345, 1), 3, rat128.RoundFloor, "1.234e4"},
+		{New(-12345, 1), 3, rat128.RoundFloor, "-1.235e4"},
+		{New(1, 8), 1, rat128.RoundHalfAwayFromZero, "1.3e-1"},
+		{New(1, 8), 1, rat128.RoundHalfToEven, "1.2e-1"},
+		{New(1, 3), 4, rat128.RoundHalfAwayFromZero, "3.3333e-1"},
+		{New(2, 3), 4, rat128.RoundHalfAwayFromZero, "6.6667e-1"},
+		{New(999, 1), 1, rat128.RoundHalfAwayFromZero, "1.0e3"},
+		{New(999, 1000), 1, rat128.RoundHalfAwayFromZero, "1.0e0"},
+		{New(999, 1000), 1, rat128.RoundTowardZero, "9.9e-1"},
+		{New(1, 10), 0, rat128.RoundHalfAwayFromZero, "1e-1"},
+		{New(1, 100), 2, rat128.RoundHalfAwayFromZero, "1.00e-2"},
+		{New(math.MaxInt64, 1), 3, rat128.RoundHalfAwayFromZero, "9.223e18"},
+		{New(1, math.MaxInt64), 3, rat128.RoundHalfAwayFromZero, "1.084e-19"},
+		{New(-1, math.MaxInt64), 0, rat128.RoundCeiling, "-1e-19"},
+	}
+	for _, c := range cases {
+		r := c.Rat
+		t.Run(fmt.Sprintf("(%s):%d:%d", r, c.Prec, c.Mode), func(t *testing.T) {
+			s := r.ScientificString(c.Prec, c.Mode)
+			if s != c.String {
+				t.Errorf("got %s, want %s", s, c.String)
+			}
+		})
+	}
+}
+
+func TestN_DigitStream(t *testing.T) {
+	cases := []struct {
+		Rat    rat128.N
+		Max    int
+		Digits string
+	}{
+		{New(0, 1), 10, ""},
+		{New(3, 1), 10, ""},
+		{New(1, 2), 10, "5"},
+		{New(-1, 8), 10, "125"},
+		{New(9, 8), 10, "125"},
+		{New(1, 3), 10, "3333333333"},
+		{New(-22, 7), 12, "142857142857"},
+		{New(1, math.MaxInt64), 25, "0000000000000000001084202"},
+		{New(1<<63-2, 1<<63-1), 25, "9999999999999999998915797"},
+	}
+	for _, c := range cases {
+		t.Run(c.Rat.String(), func(t *testing.T) {
+			s := c.Rat.DigitStream()
+			var digits []byte
+			for len(digits) < c.Max {
+				d, ok := s.Next()
+				if !ok {
+					break
+				}
+				digits = append(digits, d)
+			}
+			if string(digits) != c.Digits {
+				t.Errorf("got %s, want %s", digits, c.Digits)
+			}
+		})
+	}
+}
+
+func TestN_AsRatioOf(t *testing.T) {
+	cases := []struct {
+		Rat     rat128.N
+		UnitDen int64
+		Num     int64
+		OK      bool
+	}{
+		{New(0, 1), 7, 0, true},
+		{New(1, 2), 4, 2, true},
+		{New(-1, 2), 4, -2, true},
+		{New(3, 1), 1, 3, true},
+		{New(3, 4), 100, 75, true},
+		{New(1, 3), 100, 0, false},
+		{New(1, 2), 0, 0, false},
+		{New(1, 2), -4, 0, false},
+		{New(math.MaxInt64, 1), 2, 0, false},
+		{New(-math.MaxInt64, 2), 2, -math.MaxInt64, true},
+		{New(math.MaxInt64/2, 1), 2, math.MaxInt64 - 1, true},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s):%d", c.Rat.RationalString("_"), c.UnitDen), func(t *testing.T) {
+			num, ok := c.Rat.AsRatioOf(c.UnitDen)
+			if ok != c.OK || num != c.Num {
+				t.Errorf("got (%d, %v), want (%d, %v)", num, ok, c.Num, c.OK)
+			}
+		})
+	}
+}
+
+func TestN_IsReciprocalOf(t *testing.T) {
+	cases := []struct {
+		X, Y   rat128.N
+		Result bool
+	}{
+		{New(0, 1), New(0, 1), false},
+		{New(1, 1), New(1, 1), true},
+		{New(-1, 1), New(-1, 1), true},
+		{New(-1, 1), New(1, 1), false},
+		{New(2, 3), New(3, 2), true},
+		{New(-2, 3), New(-3, 2), true},
+		{New(-2, 3), New(3, 2), false},
+		{New(2, 3), New(2, 3), false},
+		{New(2, 3), New(3, 1), false},
+		{New(math.MaxInt64, math.MaxInt64-1), New(math.MaxInt64-1, math.MaxInt64), true},
+		{New(math.MaxInt64, 1), New(1, math.MaxInt64), true},
+		{New(math.MaxInt64, 1), New(math.MaxInt64, 1), false},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s),(%s)", c.X.RationalString("_"), c.Y.RationalString("_")), func(t *testing.T) {
+			if r := c.X.IsReciprocalOf(c.Y); r != c.Result {
+				t.Errorf("got %v, want %v", r, c.Result)
+			}
+			if r := c.Y.IsReciprocalOf(c.X); r != c.Result {
+				t.Errorf("got %v reversed, want %v", r, c.Result)
+			}
+		})
+	}
+}
+
+func TestN_Float32(t *testing.T) {
+	cases := []struct {
+		Rat   rat128.N
+		Float float32
+		Exact bool
+	}{
+		{New(0, 1), 0, true},
+		{New(1, 1), 1, true},
+		{New(-1, 1), -1, true},
+		{New(1, 2), 0.5, true},
+		{New(-3, 8), -0.375, true},
+		{New(1, 3), 1.0 / 3, false},
+		{New(-2, 3), -2.0 / 3, false},
+		{New(1<<24-1, 1), 1<<24 - 1, true},
+		{New(1<<24+1, 1), 1 << 24, false},
+		{New(1<<40, 1), 1 << 40, true},
+		{New(1<<40+1, 1), 1 << 40, false},
+		{New(1, 1<<62), 0x1p-62, true},
+		{New(3, 1<<62), 0x3p-62, true},
+		{New(1, math.MaxInt64), 0x1p-63, false},
+		{New(math.MaxInt64, 1), 0x1p63, false},
+		{New(16777217, 16777216), 1, false},
+		{New(16777219, 16777216), 1 + 0x1p-22, false},
+	}
+	for _, c := range cases {
+		t.Run(c.Rat.String(), func(t *testing.T) {
+			f, exact := c.Rat.Float32()
+			if f != c.Float || exact != c.Exact {
+				t.Errorf("got (%g, %v), want (%g, %v)", f, exact, c.Float, c.Exact)
+			}
+			if bf, _ := c.Rat.BigRat().Float32(); f != bf {
+				t.Errorf("got %g, big.Rat got %g", f, bf)
+			}
+		})
+	}
+}
+
+func TestN_Float64ULPError(t *testing.T) {
+	cases := []struct {
+		Rat  rat128.N
+		ULPs int
+	}{
+		{New(0, 1), 0},
+		{New(1, 2), 0},
+		{New(-3, 8), 0},
+		{New(1, 3), 1},
+		{New(-2, 3), 1},
+		{New(1, 10), 1},
+		{New(1<<53+1, 1), 1},
+		{New(math.MaxInt64, 1), 1},
+		{New(1, math.MaxInt64), 1},
+		{New(-2236224882650822814, 5884513245833687293), 2}, // double rounding
+	}
+	for _, c := range cases {
+		t.Run(c.Rat.String(), func(t *testing.T) {
+			v, ulps := c.Rat.Float64ULPError()
+			if f, _ := c.Rat.Float64(); v != f {
+				t.Errorf("got %g, want %g", v, f)
+			}
+			if ulps != c.ULPs {
+				t.Errorf("got %d ULPs, want %d", ulps, c.ULPs)
+			}
+		})
+	}
+}
+
+func TestN_DecimalStringPadded(t *testing.T) {
+	cases := []struct {
+		Rat       rat128.N
+		IntDigits int
+		Prec      int
+		String    string
+	}{
+		{New(15, 2), 3, 2, "007.50"},
+		{New(-15, 2), 3, 2, "-007.50"},
+		{New(15, 2), 1, 2, "7.50"},
+		{New(15, 2), 0, 2, "7.50"},
+		{New(1234, 1), 3, 1, "1234.0"},
+		{New(7, 1), 3, 0, "007"},
+		{New(-7, 1), 3, 0, "-007"},
+		{New(1, 3), 2, 3, "00.333"},
+		{New(-1, 3), 2, 0, "-00"},
+		{New(999, 100), 2, 1, "10.0"},
+	}
+	for _, c := range cases {
+		r := c.Rat
+		t.Run(fmt.Sprintf("(%s):%d:%d", r, c.IntDigits, c.Prec), func(t *testing.T) {
+			s := r.DecimalStringPadded(c.IntDigits, c.Prec)
+			if s != c.String {
+				t.Errorf("got %s, want %s", s, c.String)
+			}
+		})
+	}
+}
+
+func TestFromDigits(t *testing.T) {
+	cases := []struct {
+		Digits     string
+		PointIndex int
+		Negative   bool
+		Rat        rat128.N
+		Err        error
+	}{
+		{"0", 1, false, New(0, 1), nil},
+		{"0", 0, true, New(0, 1), nil},
+		{"1234", 1, true, New(-617, 500), nil},
+		{"1234", 4, false, New(1234, 1), nil},
+		{"1234", 0, false, New(617, 5000), nil},
+		{"1234", 6, false, New(123400, 1), nil},
+		{"1234", -2, false, New(617, 500000), nil},
+		{"00750", 3, false, New(15, 2), nil},
+		{"00000095367431640625", 0, false, New(1, 1<<20), nil},
+		{"9223372036854775807", 19, false, New(math.MaxInt64, 1), nil},
+		{"9223372036854775808", 19, false, Zero, rat128.ErrNumOverflow},
+		{"1", -19, false, Zero, rat128.ErrDenOverflow},
+		{"", 0, false, Zero, rat128.ErrFmtInvalid},
+		{"12a4", 1, false, Zero, rat128.ErrFmtInvalid},
+		{"-12", 1, false, Zero, rat128.ErrFmtInvalid},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%s:%d:%v", c.Digits, c.PointIndex, c.Negative), func(t *testing.T) {
+			r, err := rat128.FromDigits([]byte(c.Digits), c.PointIndex, c.Negative)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if c.Err == nil && r != c.Rat {
+				t.Errorf("got %v, want %v", r, c.Rat)
+			}
+		})
+	}
+}
+
+func TestFromBits(t *testing.T) {
+	bits := func(s string) []bool {
+		b := make([]bool, len(s))
+		for i := range s {
+			b[i] = s[i] == '1'
+		}
+		return b
+	}
+	ones := strings.Repeat("1", 63)
+	cases := []struct {
+		Bits       string
+		PointIndex int
+		Rat        rat128.N
+		Err        error
+	}{
+		{"0", 1, New(0, 1), nil},
+		{"1011", 2, New(11, 4), nil},
+		{"1011", 4, New(11, 1), nil},
+		{"1011", 0, New(11, 16), nil},
+		{"1011", 6, New(44, 1), nil},
+		{"1011", -2, New(11, 64), nil},
+		{"0001", 0, New(1, 16), nil},
+		{"1", -62, Zero, rat128.ErrDenOverflow},
+		{"1", -61, New(1, 1<<62), nil},
+		{ones, 63, New(math.MaxInt64, 1), nil},
+		{"0" + ones, 64, New(math.MaxInt64, 1), nil},
+		{"1" + ones, 64, Zero, rat128.ErrNumOverflow},
+		{ones, 0, Zero, rat128.ErrDenOverflow},
+		{"", 0, Zero, rat128.ErrFmtInvalid},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%s:%d", c.Bits, c.PointIndex), func(t *testing.T) {
+			r, err := rat128.FromBits(bits(c.Bits), c.PointIndex)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if c.Err == nil && r != c.Rat {
+				t.Errorf("got %v, want %v", r, c.Rat)
+			}
+		})
+	}
+}
+
+func TestParseMixedString(t *testing.T) {
+	cases := []struct {
+		String string
+		Rat    rat128.N
+		Err    error
+	}{
+		{"0", New(0, 1), nil},
+		{"2", New(2, 1), nil},
+		{"-2", New(-2, 1), nil},
+		{"1/3", New(1, 3), nil},
+		{"-1/3", New(-1, 3), nil},
+		{"2 1/3", New(7, 3), nil},
+		{"-2 1/3", New(-7, 3), nil},
+		{"2 2/6", New(7, 3), nil},
+		{"  2   1/3 ", New(7, 3), nil},
+		{"", Zero, rat128.ErrFmtInvalid},
+		{"2 1/3 4", Zero, rat128.ErrFmtInvalid},
+		{"2 -1/3", Zero, rat128.ErrFmtInvalid},
+		{"--2 1/3", Zero, rat128.ErrFmtInvalid},
+		{"2 1", Zero, rat128.ErrFmtInvalid},
+		{"2 1/0", Zero, rat128.ErrDenInvalid},
+		{"9223372036854775807 1/2", Zero, rat128.ErrNumOverflow},
+		{"abc", Zero, rat128.ErrFmtInvalid},
+		{"x/3", Zero, rat128.ErrFmtInvalid},
+		{"1/x", Zero, rat128.ErrFmtInvalid},
+		{"2 x/3", Zero, rat128.ErrFmtInvalid},
+		{"x 1/3", Zero, rat128.ErrFmtInvalid},
+		{"2 1/2/3", Zero, rat128.ErrFmtInvalid},
+		{"9223372036854775808", Zero, rat128.ErrFmtInvalid},
+		{"+2", Zero, rat128.ErrFmtInvalid},
+		{"+1/3", Zero, rat128.ErrFmtInvalid},
+		{"2 +1/3", Zero, rat128.ErrFmtInvalid},
+		{"+2 1/3", Zero, rat128.ErrFmtInvalid},
+		{"2 4/3", Zero, rat128.ErrFmtInvalid},
+		{"2 3/3", Zero, rat128.ErrFmtInvalid},
+		{"2 0/3", Zero, rat128.ErrFmtInvalid},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%q", c.String), func(t *testing.T) {
+			r, err := rat128.ParseMixedString(c.String)
+			if c.Err == nil {
+				if err != nil {
+					t.Fatalf("got unexpected error %v", err)
+				} else if r != c.Rat {
+					t.Errorf("got %v, want %v", r, c.Rat)
+				}
+			} else if !errors.Is(err, c.Err) {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			}
+		})
+	}
+}
+
+func TestN_MixedString(t *testing.T) {
+	cases := []struct {
+		Rat    rat128.N
+		String string
+	}{
+		{New(0, 1), "0"},
+		{New(2, 1), "2"},
+		{New(-2, 1), "-2"},
+		{New(1, 3), "1/3"},
+		{New(-1, 3), "-1/3"},
+		{New(7, 3), "2 1/3"},
+		{New(-7, 3), "-2 1/3"},
+		{New(math.MaxInt64, 2), "4611686018427387903 1/2"},
+	}
+	for _, c := range cases {
+		t.Run(c.Rat.String(), func(t *testing.T) {
+			s := c.Rat.MixedString()
+			if s != c.String {
+				t.Errorf("got %q, want %q", s, c.String)
+			}
+			r, err := rat128.ParseMixedString(s)
+			if err != nil {
+				t.Errorf("got unexpected error %v", err)
+			} else if r != c.Rat {
+				t.Errorf("got %v after round trip, want %v", r, c.Rat)
+			}
+		})
+	}
+}
+
+func TestTryReduceOrBig(t *testing.T) {
+	huge, _ := new(big.Int).SetString("100000000000000000000", 10)
+	cases := []struct {
+		Num, Den *big.Int
+		Rat      rat128.N
+		Big      string
+	}{
+		{big.NewInt(0), big.NewInt(1), New(0, 1), ""},
+		{big.NewInt(2), big.NewInt(4), New(1, 2), ""},
+		{big.NewInt(2), big.NewInt(-4), New(-1, 2), ""},
+		{big.NewInt(math.MinInt64), big.NewInt(2), New(-1<<62, 1), ""},
+		{big.NewInt(math.MinInt64), big.NewInt(1), Zero, "-9223372036854775808"},
+		{huge, new(big.Int).Mul(huge, big.NewInt(3)), New(1, 3), ""},
+		{huge, big.NewInt(3), Zero, "100000000000000000000/3"},
+		{big.NewInt(3), huge, Zero, "3/100000000000000000000"},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%v/%v", c.Num, c.Den), func(t *testing.T) {
+			x, r := rat128.TryReduceOrBig(c.Num, c.Den)
+			if c.Big == "" {
+				if r != nil {
+					t.Errorf("got big.Rat %v, want nil", r)
+				} else if x != c.Rat {
+					t.Errorf("got %v, want %v", x, c.Rat)
+				}
+			} else if r == nil {
+				t.Errorf("got %v, want big.Rat %s", x, c.Big)
+			} else if r.RatString() != c.Big || x != Zero {
+				t.Errorf("got (%v, %v), want (0/1, %s)", x, r.RatString(), c.Big)
+			}
+		})
+	}
+}
+
+func TestN_TryInt64Ops(t *testing.T) {
+	xs := []rat128.N{
+		New(0, 1), New(1, 1), New(-1, 1), New(2, 3), New(-2, 3), New(7, 12),
+		New(P1, P2), New(-P1*P2, P3), New(math.MaxInt64, 1), New(-math.MaxInt64, 1),
+		New(1, math.MaxInt64), New(math.MaxInt64, 2), New(-3, math.MaxInt64-1),
+	}
+	ks := []int64{0, 1, -1, 2, -2, 3, 4, 6, P3, -P2, math.MaxInt64, -math.MaxInt64, math.MinInt64}
+	// the reference results come from big.Rat, since k may not fit in N
+	ops := []struct {
+		Name string
+		Op   func(x rat128.N, k int64) (rat128.N, error)
+		Ref  func(z, x, k *big.Rat) *big.Rat
+	}{
+		{"AddInt64", rat128.N.TryAddInt64, (*big.Rat).Add},
+		{"SubInt64", rat128.N.TrySubInt64, (*big.Rat).Sub},
+		{"MulInt64", rat128.N.TryMulInt64, (*big.Rat).Mul},
+		{"DivInt64", rat128.N.TryDivInt64, func(z, x, k *big.Rat) *big.Rat {
+			if k.Sign() == 0 {
+				return nil
+			}
+			return z.Quo(x, k)
+		}},
+	}
+	for _, op := range ops {
+		for _, x := range xs {
+			for _, k := range ks {
+				t.Run(fmt.Sprintf("%s(%s,%d)", op.Name, x.RationalString("_"), k), func(t *testing.T) {
+					z, err := op.Op(x, k)
+					ref := op.Ref(new(big.Rat), x.BigRat(), new(big.Rat).SetInt64(k))
+					if ref == nil {
+						if err != rat128.ErrDivByZero {
+							t.Errorf("got (%v, %v), want error %v", z, err, rat128.ErrDivByZero)
+						}
+						return
+					}
+					want, wantErr := rat128.FromBigRat(ref)
+					if (err == nil) != (wantErr == nil) {
+						t.Errorf("got (%v, %v), want (%v, %v)", z, err, want, wantErr)
+					} else if err == nil && z != want {
+						t.Errorf("got %v, want %v", z, want)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestClosestFromFloat64(t *testing.T) {
+	cases := []struct {
+		Float    float64
+		Rat      rat128.N
+		Residual float64
+		Err      error
+	}{
+		{0, New(0, 1), 0, nil},
+		{0.375, New(3, 8), 0, nil},
+		{-12.375, New(-99, 8), 0, nil},
+		{0x1p63, New(math.MaxInt64, 1), 1, nil},
+		{-0x1p64, New(-math.MaxInt64, 1), -0x1p64 + 0x1p63 - 1, nil},
+		{0x1p-63, New(1, math.MaxInt64), 0x1p-63 - 1.0/math.MaxInt64, nil},
+		{0x1p-80, New(0, 1), 0x1p-80, nil},
+		{-0x1p-80, New(0, 1), -0x1p-80, nil},
+		{0x1p-64, New(0, 1), 0x1p-64, nil},
+		{0x1.8p-64, New(1, math.MaxInt64), 0x1.8p-64 - 1.0/math.MaxInt64, nil},
+		{math.NaN(), Zero, 0, rat128.ErrInexact},
+		{math.Inf(1), Zero, 0, rat128.ErrInexact},
+		{math.Inf(-1), Zero, 0, rat128.ErrInexact},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%g", c.Float), func(t *testing.T) {
+			r, residual, err := rat128.ClosestFromFloat64(c.Float)
+			if err != c.Err {
+				t.Fatalf("got error %v, want %v", err, c.Err)
+			}
+			if c.Err == nil && (r != c.Rat || residual != c.Residual) {
+				t.Errorf("got (%s, %g), want (%s, %g)", r, residual, c.Rat, c.Residual)
+			}
+		})
+	}
+}
+
+func TestN_CompactString(t *testing.T) {
+	cases := []struct {
+		Rat    rat128.N
+		String string
+	}{
+		{New(0, 1), "0"},
+		{New(5, 1), "5"},
+		{New(-5, 1), "-5"},
+		{New(1, 2), "1/2"},
+		{New(-7, 3), "-7/3"},
+		{New(math.MaxInt64, 1), "9223372036854775807"},
+	}
+	for _, c := range cases {
+		t.Run(c.Rat.String(), func(t *testing.T) {
+			if s := c.Rat.CompactString(); s != c.String {
+				t.Errorf("got %q, want %q", s, c.String)
+			}
+		})
+	}
+}
+
+func TestFromBigFloat(t *testing.T) {
+	cases := []struct {
+		Float string
+		Rat   rat128.N
+		Err   error
+	}{
+		{"0", New(0, 1), nil},
+		{"1", New(1, 1), nil},
+		{"-1", New(-1, 1), nil},
+		{"0.375", New(3, 8), nil},
+		{"-12.375", New(-99, 8), nil},
+		{"0x1p62", New(1<<62, 1), nil},
+		{"0x1p63", Zero, rat128.ErrNumOverflow},
+		{"-0x1p63", Zero, rat128.ErrNumOverflow},
+		{"0x7fffffffffffffff", New(math.MaxInt64, 1), nil},
+		{"0x7fffffffffffffffp-62", New(math.MaxInt64, 1<<62), nil},
+		{"0xffffffffffffffffp-62", Zero, rat128.ErrNumOverflow},
+		{"0x1p-62", New(1, 1<<62), nil},
+		{"0x1p-63", Zero, rat128.ErrDenOverflow},
+		{"+Inf", Zero, rat128.ErrNumOverflow},
+	}
+	for _, c := range cases {
+		t.Run(c.Float, func(t *testing.T) {
+			f, _, err := big.ParseFloat(c.Float, 0, 256, big.ToNearestEven)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, err := rat128.FromBigFloat(f)
+			if err != c.Err {
+				t.Fatalf("got error %v, want %v", err, c.Err)
+			}
+			if c.Err == nil && r != c.Rat {
+				t.Errorf("got value %s, want %s", r, c.Rat)
+			}
+		})
+	}
+}
+
+func TestN_TryAvg2(t *testing.T) {
+	cases := []struct {
+		X, Y, Z rat128.N
+		Err     error
+	}{
+		{New(0, 1), New(0, 1), New(0, 1), nil},
+		{New(0, 1), New(1, 1), New(1, 2), nil},
+		{New(1, 3), New(1, 2), New(5, 12), nil},
+		{New(-1, 3), New(1, 3), New(0, 1), nil},
+		{New(math.MaxInt64, 1), New(math.MaxInt64, 1), New(math.MaxInt64, 1), nil},
+		{New(math.MaxInt64, 1), New(math.MaxInt64-2, 1), New(math.MaxInt64-1, 1), nil},
+		{New(math.MaxInt64, 1), New(-math.MaxInt64, 1), New(0, 1), nil},
+		{New(-math.MaxInt64, 1), New(-math.MaxInt64+2, 1), New(-math.MaxInt64+1, 1), nil},
+		{New(-math.MaxInt64, 1), New(-math.MaxInt64+1, 1), Zero, rat128.ErrNumOverflow},
+		{New(1, math.MaxInt64), New(1, math.MaxInt64), New(1, math.MaxInt64), nil},
+		{New(1, math.MaxInt64), New(0, 1), Zero, rat128.ErrDenOverflow},
+		{New(1426217253, 2), New(1089109167714302979, 15176090870), New(5955655241829596517, 15176090870), nil},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s),(%s)", c.X.RationalString("_"), c.Y.RationalString("_")), func(t *testing.T) {
+			z, err := c.X.TryAvg2(c.Y)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if c.Err == nil && z != c.Z {
+				t.Errorf("got %v, want %v", z, c.Z)
+			}
+		})
+	}
+}
+
+func TestN_IsFloatExact(t *testing.T) {
+	cases := []struct {
+		X, Y     rat128.N
+		Add, Mul bool
+	}{
+		{New(0, 1), New(0, 1), true, true},
+		{New(1, 2), New(1, 4), true, true},
+		{New(1, 3), New(2, 3), true, false},
+		{New(1, 3), New(3, 1), false, true},
+		{New(1, 10), New(1, 5), false, false},
+		{New(1<<53, 1), New(1, 1), false, true},
+		{New(1<<53, 1), New(2, 1), true, true},
+		{New(math.MaxInt64, 1), New(1, 1), true, false},
+		{New(1<<62, 1), New(1<<62, 1), true, true},
+		{New(1, 1<<62), New(1, 1<<62), true, true},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s),(%s)", c.X.RationalString("_"), c.Y.RationalString("_")), func(t *testing.T) {
+			if r := c.X.AddIsFloatExact(c.Y); r != c.Add {
+				t.Errorf("got AddIsFloatExact()=%v, want %v", r, c.Add)
+			}
+			if r := c.X.MulIsFloatExact(c.Y); r != c.Mul {
+				t.Errorf("got MulIsFloatExact()=%v, want %v", r, c.Mul)
+			}
+		})
+	}
+}
+
+func TestN_ExactString(t *testing.T) {
+	cases := []struct {
+		Rat     rat128.N
+		String  string
+		Decimal bool
+	}{
+		{New(0, 1), "0", true},
+		{New(-7, 1), "-7", true},
+		{New(1, 2), "0.5", true},
+		{New(-3, 8), "-0.375", true},
+		{New(1, 20), "0.05", true},
+		{New(7, 625), "0.0112", true},
+		{New(1, 1<<62), "0.000000000000000000216840434497100886801490560173988342285156" +
+			"25", true},
+		{New(math.MaxInt64, 1000), "9223372036854775.807", true},
+		{New(1, 3), "1/3", false},
+		{New(-5, 6), "-5/6", false},
+	}
+	for _, c := range cases {
+		r := c.Rat
+		t.Run(r.String(), func(t *testing.T) {
+			s, dec := r.ExactString()
+			if s != c.String || dec != c.Decimal {
+				t.Errorf("got (%s, %t), want (%s, %t)", s, dec, c.String, c.Decimal)
+			}
+			if dec {
+				if br, ok := new(big.Rat).SetString(s); !ok || br.Cmp(r.BigRat()) != 0 {
+					t.Errorf("round trip: got %v, want %v", br, r)
+				}
+			}
+		})
+	}
+}
+
+func TestN_PositionRatio(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		X, Lo, Hi rat128.N
+		Z         rat128.N
+		Err       error
+	}{
+		{New(1, 2), New(0, 1), New(1, 1), New(1, 2), nil},
+		{New(3, 1), New(1, 1), New(5, 1), New(1, 2), nil},
+		{New(1, 3), New(1, 4), New(1, 2), New(1, 3), nil},
+		{New(6, 1), New(1, 1), New(5, 1), New(5, 4), nil},
+		{New(0, 1), New(1, 1), New(5, 1), New(-1, 4), nil},
+		{New(3, 1), New(5, 1), New(1, 1), New(1, 2), nil},
+		{New(0, 1), New(-M, 1), New(M, 1), New(1, 2), nil},
+		{New(M, 1), New(-M, 1), New(M, 1), New(1, 1), nil},
+		{New(1, 1), New(2, 1), New(2, 1), Zero, rat128.ErrDivByZero},
+		{New(M, 1), New(-M, 1), New(0, 1), New(2, 1), nil},
+		{New(M, 1), New(0, 1), New(1, 2), Zero, rat128.ErrNumOverflow},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s)in[%s,%s]", c.X.RationalString("_"), c.Lo.RationalString("_"), c.Hi.RationalString("_")), func(t *testing.T) {
+			z, err := c.X.PositionRatio(c.Lo, c.Hi)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if z != c.Z {
+				t.Errorf("got %v, want %v", z, c.Z)
+			}
+		})
+	}
+}
+
+func TestN_UnlerpClamped(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		X, Lo, Hi, Z rat128.N
+		Err          error
+	}{
+		{New(1, 2), New(0, 1), New(1, 1), New(1, 2), nil},
+		{New(3, 1), New(2, 1), New(6, 1), New(1, 4), nil},
+		{New(3, 1), New(6, 1), New(2, 1), New(3, 4), nil},
+		{New(2, 1), New(2, 1), New(6, 1), New(0, 1), nil},
+		{New(6, 1), New(2, 1), New(6, 1), New(1, 1), nil},
+		{New(-1, 1), New(2, 1), New(6, 1), New(0, 1), nil},
+		{New(7, 1), New(2, 1), New(6, 1), New(1, 1), nil},
+		{New(7, 1), New(6, 1), New(2, 1), New(0, 1), nil},
+		{New(-1, 1), New(6, 1), New(2, 1), New(1, 1), nil},
+		{New(M, 1), New(-M, 1), New(0, 1), New(1, 1), nil},
+		{New(-M, 1), New(0, 1), New(M, 1), New(0, 1), nil},
+		{New(0, 1), New(-M, 1), New(M, 1), New(1, 2), nil},
+		{New(1, M-1), New(1, M), New(1, M-2), Zero, rat128.ErrDenOverflow},
+		{New(1, 1), New(2, 1), New(2, 1), Zero, rat128.ErrDivByZero},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s)in(%s,%s)", c.X.RationalString("_"), c.Lo.RationalString("_"), c.Hi.RationalString("_")), func(t *testing.T) {
+			z, err := c.X.UnlerpClamped(c.Lo, c.Hi)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if z != c.Z {
+				t.Errorf("got %v, want %v", z, c.Z)
+			}
+		})
+	}
+}
+
+func TestN_TerminatesInBase(t *testing.T) {
+	cases := []struct {
+		X    rat128.N
+		Base int
+		Term bool
+		Err  error
+	}{
+		{New(3, 1), 2, true, nil},
+		{New(1, 2), 10, true, nil},
+		{New(1, 3), 10, false, nil},
+		{New(1, 3), 12, true, nil},
+		{New(5, 72), 6, true, nil},
+		{New(5, 72), 10, false, nil},
+		{New(1, 1<<62), 2, true, nil},
+		{New(1, 1<<62), 6, true, nil},
+		{New(1, 1<<62), 3, false, nil},
+		{New(-7, 1000), 10, true, nil},
+		{New(1, 7), 7, true, nil},
+		{New(1, 49), 7, true, nil},
+		{New(1, 14), 7, false, nil},
+		{New(1, 2), 1, false, rat128.ErrInvalid},
+		{New(1, 2), 0, false, rat128.ErrInvalid},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s)_%d", c.X.RationalString("_"), c.Base), func(t *testing.T) {
+			term, err := c.X.TerminatesInBase(c.Base)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if term != c.Term {
+				t.Errorf("got %t, want %t", term, c.Term)
+			}
+		})
+	}
+}
+
+func TestN_BaseString(t *testing.T) {
+	cases := []struct {
+		X      rat128.N
+		Base   int
+		Prec   int
+		Mode   rat128.RoundingMode
+		String string
+		Err    error
+	}{
+		{New(1, 2), 2, 4, rat128.RoundHalfAwayFromZero, "0.1000", nil},
+		{New(5, 1), 2, 0, rat128.RoundHalfAwayFromZero, "101", nil},
+		{New(-5, 4), 2, 3, rat128.RoundHalfAwayFromZero, "-1.010", nil},
+		{New(1, 3), 2, 4, rat128.RoundHalfAwayFromZero, "0.0101", nil},
+		{New(1, 3), 3, 2, rat128.RoundHalfAwayFromZero, "0.10", nil},
+		{New(255, 16), 16, 1, rat128.RoundHalfAwayFromZero, "f.f", nil},
+		{New(1, 3), 16, 3, rat128.RoundHalfAwayFromZero, "0.555", nil},
+		{New(2, 3), 16, 3, rat128.RoundHalfAwayFromZero, "0.aab", nil},
+		{New(2, 3), 16, 3, rat128.RoundTowardZero, "0.aaa", nil},
+		{New(35, 1), 36, 0, rat128.RoundHalfAwayFromZero, "z", nil},
+		{New(71, 2), 36, 0, rat128.RoundHalfAwayFromZero, "10", nil},
+		{New(71, 2), 36, 0, rat128.RoundHalfToEven, "10", nil},
+		{New(69, 2), 36, 0, rat128.RoundHalfToEven, "y", nil},
+		{New(-1, 3), 10, 2, rat128.RoundFloor, "-0.34", nil},
+		{New(-1, 1000), 10, 2, rat128.RoundHalfAwayFromZero, "-0.00", nil},
+		{New(1, 8), 2, 1, rat128.RoundCeiling, "0.1", nil},
+		{New(1, 2), 2, -1, rat128.RoundHalfAwayFromZero, "1", nil},
+		{New(math.MaxInt64, 1), 2, 0, rat128.RoundHalfAwayFromZero, strings.Repeat("1", 63), nil},
+		{New(1, 2), 1, 2, rat128.RoundHalfAwayFromZero, "", rat128.ErrInvalid},
+		{New(1, 2), 37, 2, rat128.RoundHalfAwayFromZero, "", rat128.ErrInvalid},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s)_%d:%d:%d", c.X.RationalString("_"), c.Base, c.Prec, c.Mode), func(t *testing.T) {
+			s, err := c.X.BaseString(c.Base, c.Prec, c.Mode)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if s != c.String {
+				t.Errorf("got %s, want %s", s, c.String)
+			}
+		})
+	}
+	// base 10 with the default mode agrees with DecimalString
+	for _, x := range []rat128.N{New(2, 3), New(-2, 3), New(1, 7), New(999, 100), New(-1, 2)} {
+		for prec := 0; prec < 4; prec++ {
+			if s, _ := x.BaseString(10, prec, rat128.RoundHalfAwayFromZero); s != x.DecimalString(prec) {
+				t.Errorf("(%v).BaseString(10, %d): got %s, want %s", x, prec, s, x.DecimalString(prec))
+			}
+		}
+	}
+}
+
+func TestN_DecimalError(t *testing.T) {
+	cases := []struct {
+		X    rat128.N
+		Prec int
+		Err  rat128.N
+		Fail error
+	}{
+		{New(1, 2), 1, Zero, nil},
+		{New(1, 2), 0, New(-1, 2), nil},
+		{New(-1, 2), 0, New(1, 2), nil},
+		{New(1, 3), 2, New(1, 300), nil},
+		{New(2, 3), 2, New(-1, 300), nil},
+		{New(-2, 3), 2, New(1, 300), nil},
+		{New(1, 7), 3, New(-1, 7000), nil},
+		{New(1, 7), -1, New(1, 7), nil},
+		{New(199, 200), 2, New(-1, 200), nil},
+		{New(-5, 2), 0, New(1, 2), nil},
+		{New(math.MaxInt64, 2), 0, New(-1, 2), nil},
+		{New(1, 3), 20, Zero, rat128.ErrDenOverflow},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s):%d", c.X.RationalString("_"), c.Prec), func(t *testing.T) {
+			e, err := c.X.DecimalError(c.Prec)
+			if err != c.Fail {
+				t.Errorf("got error %v, want %v", err, c.Fail)
+			} else if e != c.Err {
+				t.Errorf("got %v, want %v", e, c.Err)
+			}
+		})
+	}
+}
+
+func TestParseBigRatCompatible(t *testing.T) {
+	cases := []struct {
+		S   string
+		X   rat128.N
+		Err error
+	}{
+		{"3/4", New(3, 4), nil},
+		{"-6/8", New(-3, 4), nil},
+		{"0.75", New(3, 4), nil},
+		{"7.5e-1", New(3, 4), nil},
+		{"0x1.8p-1", New(3, 4), nil},
+		{"1E3", New(1000, 1), nil},
+		{"+5", New(5, 1), nil},
+		{"9223372036854775807", New(math.MaxInt64, 1), nil},
+		{"9223372036854775808", Zero, rat128.ErrNumOverflow},
+		{"1/9223372036854775808", Zero, rat128.ErrDenOverflow},
+		{"1e-19", Zero, rat128.ErrDenOverflow},
+		{"1/0", Zero, rat128.ErrFmtInvalid},
+		{"", Zero, rat128.ErrFmtInvalid},
+		{"abc", Zero, rat128.ErrFmtInvalid},
+	}
+	for _, c := range cases {
+		t.Run(c.S, func(t *testing.T) {
+			x, err := rat128.ParseBigRatCompatible(c.S)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if x != c.X {
+				t.Errorf("got %v, want %v", x, c.X)
+			}
+		})
+	}
+}
+
+func TestN_ReciprocalDiff(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		X, Y rat128.N
+		Z    rat128.N
+		Err  error
+	}{
+		{New(2, 1), New(3, 1), New(1, 6), nil},
+		{New(1, 2), New(1, 3), New(-1, 1), nil},
+		{New(-2, 5), New(3, 7), New(-29, 6), nil},
+		{New(5, 1), New(5, 1), Zero, nil},
+		{New(1<<20*2097155, 1), New(1<<20*5242883, 1), New(3, 10995138297865), nil},
+		{New(3557850, 2990734309), New(-11287614734, 434340683), New(8439950492686680089, 10039910020340475), nil},
+		{New(1, M), New(-1, M), Zero, rat128.ErrNumOverflow},
+		{New(0, 1), New(1, 1), Zero, rat128.ErrDivByZero},
+		{New(1, 1), New(0, 1), Zero, rat128.ErrDivByZero},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s),(%s)", c.X.RationalString("_"), c.Y.RationalString("_")), func(t *testing.T) {
+			z, err := c.X.ReciprocalDiff(c.Y)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if z != c.Z {
+				t.Errorf("got %v, want %v", z, c.Z)
+			}
+		})
+	}
+	// the naive form overflows where ReciprocalDiff does not
+	x, y := New(1<<20*2097155, 1), New(1<<20*5242883, 1)
+	if _, err := x.Inv().TrySub(y.Inv()); err == nil {
+		t.Errorf("naive form unexpectedly succeeded")
+	}
+}
+
+func TestN_EqualMagnitude(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		X, Y rat128.N
+		Eq   bool
+	}{
+		{New(0, 1), New(0, 1), true},
+		{New(1, 2), New(1, 2), true},
+		{New(1, 2), New(-1, 2), true},
+		{New(-M, 1), New(M, 1), true},
+		{New(-1, M), New(-1, M), true},
+		{New(1, 2), New(1, 3), false},
+		{New(1, 2), New(2, 1), false},
+		{New(-1, 2), New(0, 1), false},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s),(%s)", c.X.RationalString("_"), c.Y.RationalString("_")), func(t *testing.T) {
+			if eq := c.X.EqualMagnitude(c.Y); eq != c.Eq {
+				t.Errorf("got %t, want %t", eq, c.Eq)
+			}
+			if eq := rat128.EqualMagnitude(c.Y, c.X); eq != c.Eq {
+				t.Errorf("got %t from function, want %t", eq, c.Eq)
+			}
+			if want := c.X.Abs() == c.Y.Abs(); c.Eq != want {
+				t.Errorf("case disagrees with Abs: %t vs %t", c.Eq, want)
+			}
+		})
+	}
+}
+
+func TestN_ExactSqrt(t *testing.T) {
+	const S = 3037000499 // floor(sqrt(math.MaxInt64))
+	cases := []struct {
+		X, Z rat128.N
+		OK   bool
+	}{
+		{New(0, 1), New(0, 1), true},
+		{New(1, 1), New(1, 1), true},
+		{New(4, 9), New(2, 3), true},
+		{New(2, 1), Zero, false},
+		{New(1, 8), Zero, false},
+		{New(-4, 1), Zero, false},
+		{New(S*S, (S-1)*(S-1)), New(S, S-1), true},
+		{New(S*S+1, 1), Zero, false},
+		{New(S*S-1, 1), Zero, false},
+		{New(math.MaxInt64, 1), Zero, false},
+	}
+	for _, c := range cases {
+		t.Run(c.X.String(), func(t *testing.T) {
+			z, ok := c.X.ExactSqrt()
+			if z != c.Z || ok != c.OK {
+				t.Errorf("got (%v, %t), want (%v, %t)", z, ok, c.Z, c.OK)
+			}
+		})
+	}
+}
+
+func TestN_TryPowRat(t *testing.T) {
+	const (
+		M = math.MaxInt64
+		S = 3037000499          // floor(sqrt(math.MaxInt64))
+		T = 4052555153018976267 // 3^39
+	)
+	cases := []struct {
+		X, Exp, Z rat128.N
+		Err       error
+	}{
+		{New(8, 27), New(2, 3), New(4, 9), nil},
+		{New(8, 27), New(-2, 3), New(9, 4), nil},
+		{New(-8, 27), New(1, 3), New(-2, 3), nil},
+		{New(-8, 27), New(2, 3), New(4, 9), nil},
+		{New(-8, 1), New(-1, 3), New(-1, 2), nil},
+		{New(3, 2), New(3, 1), New(27, 8), nil},
+		{New(S*S, 1), New(1, 2), New(S, 1), nil},
+		{New(T, 1), New(1, 39), New(3, 1), nil},
+		{New(T, 1), New(1, 13), New(27, 1), nil},
+		{New(1, 1<<62), New(1, 62), New(1, 2), nil},
+		{New(2, 1), New(62, 1), New(1<<62, 1), nil},
+		{New(1, 1), New(1, M), New(1, 1), nil},
+		{New(-1, 1), New(1, M), New(-1, 1), nil},
+		{New(5, 7), Zero, New(1, 1), nil},
+		{Zero, Zero, New(1, 1), nil},
+		{Zero, New(1, 2), Zero, nil},
+		{Zero, New(-1, 1), Zero, rat128.ErrDivByZero},
+		{New(2, 1), New(1, 2), Zero, rat128.ErrInexact},
+		{New(4, 3), New(1, 2), Zero, rat128.ErrInexact},
+		{New(-4, 1), New(1, 2), Zero, rat128.ErrInexact},
+		{New(2, 1), New(1, M), Zero, rat128.ErrInexact},
+		{New(T+1, 1), New(1, 39), Zero, rat128.ErrInexact},
+		{New(2, 1), New(63, 1), Zero, rat128.ErrNumOverflow},
+		{New(1, 2), New(63, 1), Zero, rat128.ErrDenOverflow},
+		{New(1, 2), New(-63, 1), Zero, rat128.ErrNumOverflow},
+		{New(2, 1), New(M, 1), Zero, rat128.ErrNumOverflow},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s)^(%s)", c.X.RationalString("_"), c.Exp.RationalString("_")), func(t *testing.T) {
+			z, err := c.X.TryPowRat(c.Exp)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if z != c.Z {
+				t.Errorf("got %v, want %v", z, c.Z)
+			}
+		})
+	}
+}
+
+func TestN_GeometricMean2(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		X, Y rat128.N
+		Z    rat128.N
+		OK   bool
+		Err  error
+	}{
+		{New(2, 1), New(8, 1), New(4, 1), true, nil},
+		{New(1, 2), New(1, 8), New(1, 4), true, nil},
+		{New(2, 3), New(3, 2), New(1, 1), true, nil},
+		{New(-2, 1), New(-8, 1), New(4, 1), true, nil},
+		{New(0, 1), New(-5, 1), New(0, 1), true, nil},
+		{New(2, 1), New(3, 1), Zero, false, nil},
+		{New(M, 1), New(M, 1), New(M, 1), true, nil},
+		{New(1, M), New(1, M), New(1, M), true, nil},
+		{New(M, 2), New(M, 8), New(M, 4), true, nil},
+		{New(-1, 1), New(1, 1), Zero, false, rat128.ErrInvalid},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s),(%s)", c.X.RationalString("_"), c.Y.RationalString("_")), func(t *testing.T) {
+			z, ok, err := c.X.GeometricMean2(c.Y)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if z != c.Z || ok != c.OK {
+				t.Errorf("got (%v, %t), want (%v, %t)", z, ok, c.Z, c.OK)
+			}
+		})
+	}
+}
+
+func TestN_CmpDecimalString(t *testing.T) {
+	cases := []struct {
+		X   rat128.N
+		S   string
+		Cmp int
+		Err error
+	}{
+		{New(1, 2), "0.5", 0, nil},
+		{New(1, 2), "0.4", 1, nil},
+		{New(1, 2), "-1", 1, nil},
+		{New(1, 3), "0.333333333333333333333333333333", 1, nil},
+		{New(1, 3), "0.333333333333333333333333333334", -1, nil},
+		{New(1, 8), "1.25e-1", 0, nil},
+		{New(math.MaxInt64, 1), "9223372036854775808", -1, nil},
+		{New(math.MaxInt64, 1), "9223372036854775806.9999999999999", 1, nil},
+		{New(-1, 1), "-1.000000000000000000000000000001", 1, nil},
+		{New(1, 1), "one", 0, rat128.ErrFmtInvalid},
+		{New(1, 1), "", 0, rat128.ErrFmtInvalid},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s)vs%s", c.X.RationalString("_"), c.S), func(t *testing.T) {
+			cmp, err := c.X.CmpDecimalString(c.S)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if cmp != c.Cmp {
+				t.Errorf("got %d, want %d", cmp, c.Cmp)
+			}
+		})
+	}
+}
+
+func TestN_Blend(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		X, Y, W rat128.N
+		Z       rat128.N
+		Err     error
+	}{
+		{New(0, 1), New(1, 1), New(1, 4), New(1, 4), nil},
+		{New(1, 2), New(3, 2), New(1, 2), New(1, 1), nil},
+		{New(2, 1), New(4, 1), New(3, 2), New(5, 1), nil},
+		{New(2, 1), New(4, 1), New(-1, 2), New(1, 1), nil},
+		{New(-M, 1), New(M, 1), New(0, 1), New(-M, 1), nil},
+		{New(-M, 1), New(M, 1), New(1, 1), New(M, 1), nil},
+		{New(-M, 1), New(M, 1), New(1, 2), New(0, 1), nil},
+		{New(-M, 1), New(M, 1), New(3, 4), New(M, 2), nil},
+		{New(M, 1), New(M-1, 1), New(-1, 1), Zero, rat128.ErrNumOverflow},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s),(%s),(%s)", c.X.RationalString("_"), c.Y.RationalString("_"), c.W.RationalString("_")), func(t *testing.T) {
+			z, err := c.X.Blend(c.Y, c.W)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if z != c.Z {
+				t.Errorf("got %v, want %v", z, c.Z)
+			}
+		})
+	}
+}
+
+func TestN_TryMod(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		X, Y rat128.N
+		Z    rat128.N
+		Err  error
+	}{
+		{New(7, 1), New(3, 1), New(1, 1), nil},
+		{New(-7, 1), New(3, 1), New(2, 1), nil},
+		{New(7, 1), New(-3, 1), New(-2, 1), nil},
+		{New(-7, 1), New(-3, 1), New(-1, 1), nil},
+		{New(7, 2), New(1, 1), New(1, 2), nil},
+		{New(-7, 2), New(1, 1), New(1, 2), nil},
+		{New(5, 6), New(1, 4), New(1, 12), nil},
+		{New(1, 3), New(1, 3), New(0, 1), nil},
+		{New(M, 1), New(1, M), New(0, 1), nil},
+		{New(M, 1), New(2, 3), New(1, 3), nil},
+		{New(-M, 1), New(M-1, M), New(M-2, M), nil},
+		{New(1, M), New(1, M-1), New(1, M), nil},
+		{New(1, M-1), New(1, M), Zero, rat128.ErrDenOverflow},
+		{New(1, 1), Zero, Zero, rat128.ErrDivByZero},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s)%%(%s)", c.X.RationalString("_"), c.Y.RationalString("_")), func(t *testing.T) {
+			z, err := c.X.TryMod(c.Y)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if z != c.Z {
+				t.Errorf("got %v, want %v", z, c.Z)
+			}
+		})
+	}
+}
+
+func TestN_RelativeDifference(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		X, Y rat128.N
+		Z    rat128.N
+		Err  error
+	}{
+		{New(11, 10), New(1, 1), New(1, 10), nil},
+		{New(9, 10), New(1, 1), New(-1, 10), nil},
+		{New(-11, 10), New(-1, 1), New(1, 10), nil},
+		{New(-1, 1), New(1, 1), New(-2, 1), nil},
+		{New(1, 3), New(1, 3), New(0, 1), nil},
+		{New(0, 1), New(5, 7), New(-1, 1), nil},
+		{New(M, 1), New(-M, 1), New(-2, 1), nil},
+		{New(1, M), New(1, M-1), New(-1, M), nil},
+		{New(M, 1), New(1, 2), Zero, rat128.ErrNumOverflow},
+		{New(1, 1), New(0, 1), Zero, rat128.ErrDivByZero},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s),(%s)", c.X.RationalString("_"), c.Y.RationalString("_")), func(t *testing.T) {
+			z, err := c.X.RelativeDifference(c.Y)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if z != c.Z {
+				t.Errorf("got %v, want %v", z, c.Z)
+			}
+		})
+	}
+}
+
+func TestN_SecantStep(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		X0, FX0, X1, FX1, Z rat128.N
+		Err                 error
+	}{
+		// f(x) = x^2 - 2
+		{New(1, 1), New(-1, 1), New(2, 1), New(2, 1), New(4, 3), nil},
+		{New(2, 1), New(2, 1), New(4, 3), New(-2, 9), New(7, 5), nil},
+		// f(x) = 3x - 1 is linear, so one step finds the root
+		{New(0, 1), New(-1, 1), New(1, 1), New(2, 1), New(1, 3), nil},
+		{New(1, 3), New(1, 1), New(1, 2), New(-1, 1), New(5, 12), nil},
+		{New(-M, 1), New(-1, 1), New(M, 1), New(1, 1), Zero, nil},
+		{Zero, New(1, M), New(1, 1), New(-1, M-1), Zero, rat128.ErrDenOverflow},
+		{New(1, 1), New(2, 1), New(3, 1), New(2, 1), Zero, rat128.ErrDivByZero},
+	}
+	for _, c := range cases {
+		name := fmt.Sprintf("(%s,%s)(%s,%s)", c.X0.RationalString("_"), c.FX0.RationalString("_"), c.X1.RationalString("_"), c.FX1.RationalString("_"))
+		t.Run(name, func(t *testing.T) {
+			z, err := c.X0.SecantStep(c.FX0, c.X1, c.FX1)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if z != c.Z {
+				t.Errorf("got %v, want %v", z, c.Z)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package rat128
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+// RoundingMode determines how a value is rounded when it cannot be
+// represented exactly at the requested precision.
+// The zero value is RoundHalfAwayFromZero, which matches DecimalString.
+type RoundingMode int
+
+// Rounding modes supported by this package. Any other value of RoundingMode
+// behaves like RoundHalfAwayFromZero.
+const (
+	// RoundHalfAwayFromZero rounds to nearest, with ties away from zero.
+	RoundHalfAwayFromZero RoundingMode = iota
+	// RoundHalfToEven rounds to nearest, with ties to even.
+	RoundHalfToEven
+	// RoundTowardZero truncates.
+	RoundTowardZero
+	// RoundAwayFromZero rounds up in magnitude.
+	RoundAwayFromZero
+	// RoundFloor rounds toward negative infinity.
+	RoundFloor
+	// RoundCeiling rounds toward positive infinity.
+	RoundCeiling
+)
+
+// roundsAway reports whether an inexact quotient, truncated toward zero,
+// should have its magnitude incremented by one to be rounded according to
+// mode. The exact quotient is negative if neg is true, the truncated quotient
+// is odd if odd is true, and half is the comparison of the discarded fraction
+// against one half, in the manner of N.Cmp.
+func (mode RoundingMode) roundsAway(neg, odd bool, half int) bool {
+	switch mode {
+	case RoundHalfToEven:
+		return half > 0 || (half == 0 && odd)
+	case RoundTowardZero:
+		return false
+	case RoundAwayFromZero:
+		return true
+	case RoundFloor:
+		return neg
+	case RoundCeiling:
+		return !neg
+	default:
+		return half >= 0
+	}
+}
+
+// Round returns x rounded to an integer according to mode.
+func (x N) Round(mode RoundingMode) N {
+	m, n := x.Num(), x.Den()
+	if n == 1 {
+		return x
+	}
+	// since n > 1, the magnitude of q is at most math.MaxInt64/2, so it
+	// can't overflow when incremented
+	q, r := m/n, abs64(m%n)
+	// r < 2^63, so 2*r can't overflow uint64
+	var half int
+	switch r2 := uint64(r) * 2; {
+	case r2 < uint64(n):
+		half = -1
+	case r2 > uint64(n):
+		half = 1
+	}
+	if mode.roundsAway(m < 0, q%2 != 0, half) {
+		q += sgn64(m)
+	}
+	return N{q, 0}
+}
+
+// NearestWithDen returns the value nearest to x that can be written as k/d
+// for some integer k and some d in allowedDens. For each d, k is chosen by
+// rounding x*d according to mode, and then the candidate nearest to x wins,
+// with ties going to the smaller d. Thus, with RoundFloor, the
+// result is the largest candidate no greater than x.
+// NearestWithDen returns ErrEmpty if allowedDens is empty, ErrDenInvalid if
+// any d is not positive, and an overflow error if a candidate k/d does not
+// fit, even in lowest terms.
+func (x N) NearestWithDen(allowedDens []int64, mode RoundingMode) (N, error) {
+	if len(allowedDens) == 0 {
+		return N{}, ErrEmpty
+	}
+	var best N
+	var bestDen int64
+	for _, d := range allowedDens {
+		if d <= 0 {
+			return N{}, ErrDenInvalid
+		}
+		c, err := x.roundWithDen(d, mode)
+		if err != nil {
+			return N{}, fmt.Errorf("rounding to denominator %d: %w", d, err)
+		}
+		if bestDen == 0 {
+			best, bestDen = c, d
+			continue
+		}
+		if cmp := cmpDist(x, c, best); cmp < 0 || (cmp == 0 && d < bestDen) {
+			best, bestDen = c, d
+		}
+	}
+	return best, nil
+}
+
+// roundWithDen returns k/d, where k is x*d rounded according to mode. Only the
+// fractional part of x is scaled by d, so that the result can be found
+// whenever it fits, even if x*d itself does not.
+func (x N) roundWithDen(d int64, mode RoundingMode) (N, error) {
+	m, n := x.Num(), x.Den()
+	q, r := m/n, abs64(m%n)
+	// r < n, so r*d < n*2^64 and the quotient fits in 64 bits; moreover, it
+	// is less than d, so it can be incremented without overflow
+	hi, lo := bits.Mul64(uint64(r), uint64(d))
+	k, rem := bits.Div64(hi, lo, uint64(n))
+	if rem != 0 {
+		// rem < n < 2^63, so 2*rem can't overflow uint64
+		var half int
+		switch rem2 := rem * 2; {
+		case rem2 < uint64(n):
+			half = -1
+		case rem2 > uint64(n):
+			half = 1
+		}
+		// the truncated value of x*d is q*d+k (or q*d-k), whose parity
+		// doesn't depend on the sign or on wrapping around
+		odd := (uint64(q)*uint64(d)+k)%2 != 0
+		if mode.roundsAway(m < 0, odd, half) {
+			k++
+		}
+	}
+	f, err := Try(int64(k), d)
+	if err != nil {
+		return N{}, err
+	}
+	if m < 0 {
+		f = f.Neg()
+	}
+	return N{q, 0}.TryAdd(f)
+}
+
+// simpleDens are the denominators allowed by NearestSimpleFraction.
+var simpleDens = []int64{1, 2, 3, 4, 5, 6, 7, 8, 9}
+
+// NearestSimpleFraction returns the fraction nearest to x whose denominator
+// is between 1 and 9 inclusive, such as 1/2, 2/3, or 7/8. If two such
+// fractions are equally near, the one with the smaller denominator wins; if
+// they have the same denominator, the one farther from zero wins.
+// NearestSimpleFraction is like NearestWithDen with the denominators 1
+// through 9 and RoundHalfAwayFromZero, and returns the same errors.
+func (x N) NearestSimpleFraction() (N, error) {
+	return x.NearestWithDen(simpleDens, RoundHalfAwayFromZero)
+}
+
+// RoundToMultiple returns the multiple of step nearest to x according to
+// mode, which is x/step rounded to an integer and then multiplied by step.
+// For example, 7/10 rounded to a multiple of 1/4 is 3/4.
+// RoundToMultiple returns ErrDivByZero if step is zero, and an overflow
+// error if x/step or the result does not fit.
+func (x N) RoundToMultiple(step N, mode RoundingMode) (N, error) {
+	q, err := x.TryDiv(step)
+	if err != nil {
+		return N{}, err
+	}
+	return q.Round(mode).TryMul(step)
+}
+
+// FromMeasurement returns the multiple of resolution nearest to the float64
+// reading v, rounding halfway cases away from zero. The division by
+// resolution is done exactly, before any rounding, so float noise in v only
+// matters if it moves v across the midpoint between two multiples.
+// For example, FromMeasurement(0.30000000000000004, 1/20) is 3/10.
+// FromMeasurement returns ErrInexact if v is NaN or infinite, ErrDivByZero if
+// resolution is zero, and an overflow error if the result does not fit.
+func FromMeasurement(v float64, resolution N) (N, error) {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return N{}, ErrInexact
+	} else if resolution.IsZero() {
+		return N{}, ErrDivByZero
+	}
+	res := resolution.BigRat()
+	q := new(big.Rat).SetFloat64(v)
+	q.Quo(q, res)
+	// round q to the nearest integer k, with ties away from zero
+	den := q.Denom()
+	k, r := new(big.Int).QuoRem(q.Num(), den, new(big.Int))
+	if r.Lsh(r.Abs(r), 1).Cmp(den) >= 0 {
+		k.Add(k, big.NewInt(int64(q.Sign())))
+	}
+	return FromBigRat(res.Mul(res, new(big.Rat).SetInt(k)))
+}
+
+// IsMultipleOf returns true if x is an integer multiple of step, that is, if
+// x/step is an integer. It returns false if step is zero.
+func (x N) IsMultipleOf(step N) bool {
+	if step.IsZero() {
+		return false
+	}
+	// x/step = (mx*ns)/(nx*ms) is an integer iff nx*ms divides mx*ns; since
+	// mx and nx are coprime, and likewise ms and ns, this is the same as nx
+	// dividing ns and ms dividing mx, which avoids wide multiplication
+	return step.Den()%x.Den() == 0 && x.Num()%step.Num() == 0
+}
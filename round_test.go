@@ -0,0 +1,223 @@
+package rat128_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/kbolino/rat128"
+)
+
+func TestN_Round(t *testing.T) {
+	modes := []rat128.RoundingMode{
+		rat128.RoundHalfAwayFromZero,
+		rat128.RoundHalfToEven,
+		rat128.RoundTowardZero,
+		rat128.RoundAwayFromZero,
+		rat128.RoundFloor,
+		rat128.RoundCeiling,
+	}
+	cases := []struct {
+		X rat128.N
+		Z [6]int64
+	}{
+		{New(0, 1), [6]int64{0, 0, 0, 0, 0, 0}},
+		{New(2, 1), [6]int64{2, 2, 2, 2, 2, 2}},
+		{New(-2, 1), [6]int64{-2, -2, -2, -2, -2, -2}},
+		{New(1, 3), [6]int64{0, 0, 0, 1, 0, 1}},
+		{New(-1, 3), [6]int64{0, 0, 0, -1, -1, 0}},
+		{New(1, 2), [6]int64{1, 0, 0, 1, 0, 1}},
+		{New(-1, 2), [6]int64{-1, 0, 0, -1, -1, 0}},
+		{New(3, 2), [6]int64{2, 2, 1, 2, 1, 2}},
+		{New(-3, 2), [6]int64{-2, -2, -1, -2, -2, -1}},
+		{New(5, 2), [6]int64{3, 2, 2, 3, 2, 3}},
+		{New(-5, 2), [6]int64{-3, -2, -2, -3, -3, -2}},
+		{New(5, 3), [6]int64{2, 2, 1, 2, 1, 2}},
+		{New(-5, 3), [6]int64{-2, -2, -1, -2, -2, -1}},
+		{New(math.MaxInt64, 2), [6]int64{1 << 62, 1 << 62, 1<<62 - 1, 1 << 62, 1<<62 - 1, 1 << 62}},
+		{New(math.MaxInt64-1, math.MaxInt64), [6]int64{1, 1, 0, 1, 0, 1}},
+		{New(1, math.MaxInt64), [6]int64{0, 0, 0, 1, 0, 1}},
+	}
+	for _, c := range cases {
+		for i, mode := range modes {
+			t.Run(fmt.Sprintf("(%s):%d", c.X.RationalString("_"), mode), func(t *testing.T) {
+				if z := c.X.Round(mode); z != New(c.Z[i], 1) {
+					t.Errorf("got %v, want %d", z, c.Z[i])
+				}
+			})
+		}
+	}
+}
+
+func TestN_NearestWithDen(t *testing.T) {
+	ruler := []int64{1, 2, 4, 8, 16}
+	cases := []struct {
+		X    rat128.N
+		Dens []int64
+		Mode rat128.RoundingMode
+		Z    rat128.N
+		Err  error
+	}{
+		{New(1, 3), ruler, rat128.RoundHalfAwayFromZero, New(5, 16), nil},
+		{New(1, 3), ruler, rat128.RoundFloor, New(5, 16), nil},
+		{New(1, 3), ruler, rat128.RoundCeiling, New(3, 8), nil},
+		{New(-1, 3), ruler, rat128.RoundHalfAwayFromZero, New(-5, 16), nil},
+		{New(1, 2), ruler, rat128.RoundFloor, New(1, 2), nil},
+		{New(7, 10), []int64{3, 5}, rat128.RoundHalfAwayFromZero, New(2, 3), nil},
+		{New(1, 2), []int64{3, 5}, rat128.RoundHalfAwayFromZero, New(3, 5), nil},
+		{New(5, 12), []int64{2, 3}, rat128.RoundHalfAwayFromZero, New(1, 2), nil},
+		{New(5, 12), []int64{3, 2}, rat128.RoundHalfAwayFromZero, New(1, 2), nil},
+		{New(1, 2), []int64{3, 5}, rat128.RoundHalfToEven, New(2, 5), nil},
+		{New(1, 1), nil, rat128.RoundFloor, Zero, rat128.ErrEmpty},
+		{New(1, 1), []int64{2, 0}, rat128.RoundFloor, Zero, rat128.ErrDenInvalid},
+		{New(math.MaxInt64, 1), []int64{2}, rat128.RoundFloor, New(math.MaxInt64, 1), nil},
+		{New(math.MaxInt64/8, 1), []int64{1, 2, 3, 4, 5, 6, 7, 8, 9}, rat128.RoundFloor, New(math.MaxInt64/8, 1), nil},
+		{New(-math.MaxInt64, 2), []int64{4}, rat128.RoundHalfToEven, New(-math.MaxInt64, 2), nil},
+		{New(math.MaxInt64, 3), []int64{2}, rat128.RoundHalfToEven, New(6148914691236517205, 2), nil},
+		{New(math.MaxInt64-1, math.MaxInt64), []int64{math.MaxInt64}, rat128.RoundFloor, New(math.MaxInt64-1, math.MaxInt64), nil},
+		{New(math.MaxInt64, 2), []int64{3}, rat128.RoundCeiling, Zero, rat128.ErrNumOverflow},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%v:%v:%d", c.X, c.Dens, c.Mode), func(t *testing.T) {
+			z, err := c.X.NearestWithDen(c.Dens, c.Mode)
+			if !errors.Is(err, c.Err) {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if z != c.Z {
+				t.Errorf("got %v, want %v", z, c.Z)
+			}
+		})
+	}
+}
+
+func TestN_NearestSimpleFraction(t *testing.T) {
+	cases := []struct {
+		X, Z rat128.N
+		Err  error
+	}{
+		{New(0, 1), New(0, 1), nil},
+		{New(1, 2), New(1, 2), nil},
+		{New(333, 1000), New(1, 3), nil},
+		{New(-333, 1000), New(-1, 3), nil},
+		{New(7, 10), New(5, 7), nil},
+		{New(1, 20), New(0, 1), nil},
+		{New(1, 18), New(0, 1), nil},
+		{New(1, 6), New(1, 6), nil},
+		{New(3, 19), New(1, 6), nil},
+		{New(22, 7), New(22, 7), nil},
+		{New(314159, 100000), New(22, 7), nil},
+		{New(math.MaxInt64, 1), New(math.MaxInt64, 1), nil},
+		{New(math.MaxInt64/8, 1), New(math.MaxInt64/8, 1), nil},
+		{New(-math.MaxInt64, 1), New(-math.MaxInt64, 1), nil},
+		{New(math.MaxInt64, 2), Zero, rat128.ErrNumOverflow},
+	}
+	for _, c := range cases {
+		t.Run(c.X.String(), func(t *testing.T) {
+			z, err := c.X.NearestSimpleFraction()
+			if !errors.Is(err, c.Err) {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if z != c.Z {
+				t.Errorf("got %v, want %v", z, c.Z)
+			}
+		})
+	}
+}
+
+func TestN_RoundToMultiple(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		X, Step rat128.N
+		Mode    rat128.RoundingMode
+		Z       rat128.N
+		Err     error
+	}{
+		{New(7, 10), New(1, 4), rat128.RoundHalfToEven, New(3, 4), nil},
+		{New(5, 8), New(1, 4), rat128.RoundHalfToEven, New(1, 2), nil},
+		{New(5, 8), New(1, 4), rat128.RoundHalfAwayFromZero, New(3, 4), nil},
+		{New(-5, 8), New(1, 4), rat128.RoundHalfAwayFromZero, New(-3, 4), nil},
+		{New(-5, 8), New(1, 4), rat128.RoundFloor, New(-3, 4), nil},
+		{New(7, 10), New(-1, 4), rat128.RoundHalfToEven, New(3, 4), nil},
+		{New(17, 1), New(5, 1), rat128.RoundHalfAwayFromZero, New(15, 1), nil},
+		{New(17, 1), New(5, 1), rat128.RoundCeiling, New(20, 1), nil},
+		{New(1, 3), New(1, 3), rat128.RoundTowardZero, New(1, 3), nil},
+		{New(1, 1), Zero, rat128.RoundHalfToEven, Zero, rat128.ErrDivByZero},
+		{New(M, 1), New(1, 2), rat128.RoundHalfToEven, Zero, rat128.ErrNumOverflow},
+		{New(M, 1), New(2, 1), rat128.RoundCeiling, Zero, rat128.ErrNumOverflow},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s)/(%s):%d", c.X.RationalString("_"), c.Step.RationalString("_"), c.Mode), func(t *testing.T) {
+			z, err := c.X.RoundToMultiple(c.Step, c.Mode)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if z != c.Z {
+				t.Errorf("got %v, want %v", z, c.Z)
+			}
+		})
+	}
+}
+
+func TestN_IsMultipleOf(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		X, Step rat128.N
+		Is      bool
+	}{
+		{New(0, 1), New(1, 3), true},
+		{New(3, 4), New(1, 4), true},
+		{New(3, 4), New(-1, 4), true},
+		{New(-3, 4), New(3, 8), true},
+		{New(1, 2), New(1, 4), true},
+		{New(1, 4), New(1, 2), false},
+		{New(2, 3), New(1, 2), false},
+		{New(6, 1), New(3, 2), true},
+		{New(6, 1), New(4, 1), false},
+		{New(M, 1), New(1, M), true},
+		{New(1, M), New(1, M), true},
+		{New(M-1, M), New(1, M), true},
+		{New(1, M), New(1, M-1), false},
+		{New(1, 1), Zero, false},
+		{Zero, Zero, false},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s),(%s)", c.X.RationalString("_"), c.Step.RationalString("_")), func(t *testing.T) {
+			if is := c.X.IsMultipleOf(c.Step); is != c.Is {
+				t.Errorf("got %t, want %t", is, c.Is)
+			}
+		})
+	}
+}
+
+func TestFromMeasurement(t *testing.T) {
+	cases := []struct {
+		V          float64
+		Resolution rat128.N
+		Z          rat128.N
+		Err        error
+	}{
+		{0.30000000000000004, New(1, 20), New(3, 10), nil},
+		{0.1, New(1, 10), New(1, 10), nil},
+		{12.3456, New(1, 100), New(247, 20), nil},
+		{-12.3456, New(1, 100), New(-247, 20), nil},
+		{0.7, New(-1, 4), New(3, 4), nil},
+		{2.5, New(1, 1), New(3, 1), nil},
+		{-2.5, New(1, 1), New(-3, 1), nil},
+		{0.125, New(1, 4), New(1, 4), nil},
+		{1.0 / 3, New(1, 3), New(1, 3), nil},
+		{1e-30, New(1, 1000), Zero, nil},
+		{0, New(1, 7), Zero, nil},
+		{1e20, New(1, 1), Zero, rat128.ErrNumOverflow},
+		{1, Zero, Zero, rat128.ErrDivByZero},
+		{math.NaN(), New(1, 1), Zero, rat128.ErrInexact},
+		{math.Inf(-1), New(1, 1), Zero, rat128.ErrInexact},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%g@(%s)", c.V, c.Resolution.RationalString("_")), func(t *testing.T) {
+			z, err := rat128.FromMeasurement(c.V, c.Resolution)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if z != c.Z {
+				t.Errorf("got %v, want %v", z, c.Z)
+			}
+		})
+	}
+}
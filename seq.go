@@ -0,0 +1,89 @@
+package rat128
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// HarmonicNumber returns the nth harmonic number, 1 + 1/2 + ... + 1/n.
+// The denominators grow quickly, so only the first 46 harmonic numbers fit;
+// HarmonicNumber returns an overflow error for n > 46, and ErrInvalid for
+// negative n. The 0th harmonic number is 0.
+func HarmonicNumber(n int) (N, error) {
+	if n < 0 {
+		return N{}, ErrInvalid
+	}
+	var h N
+	for k := 1; k <= n; k++ {
+		var err error
+		if h, err = h.TryAdd(N{1, int64(k) - 1}); err != nil {
+			return N{}, fmt.Errorf("adding 1/%d: %w", k, err)
+		}
+	}
+	return h, nil
+}
+
+// bernoulliEven holds the Bernoulli numbers B0, B2, B4, ..., B34, which are
+// all of the nonzero ones that fit except B1.
+var bernoulliEven = [...]N{
+	{1, 0},
+	{1, 5},
+	{-1, 29},
+	{1, 41},
+	{-1, 29},
+	{5, 65},
+	{-691, 2729},
+	{7, 5},
+	{-3617, 509},
+	{43867, 797},
+	{-174611, 329},
+	{854513, 137},
+	{-236364091, 2729},
+	{8553103, 5},
+	{-23749461029, 869},
+	{8615841276005, 14321},
+	{-7709321041217, 509},
+	{2577687858367, 5},
+}
+
+// Bernoulli returns the nth Bernoulli number and true if it fits, or else 0
+// and false. The convention B1 == -1/2 is used. The Bernoulli numbers with
+// odd n > 1 are all zero, but the rest grow quickly, so B34 is the largest
+// nonzero one that fits. Bernoulli also returns false for negative n.
+func Bernoulli(n int) (N, bool) {
+	switch {
+	case n == 1:
+		return N{-1, 1}, true
+	case n < 0:
+		return N{}, false
+	case n%2 == 1:
+		return N{}, true
+	case n/2 < len(bernoulliEven):
+		return bernoulliEven[n/2], true
+	}
+	return N{}, false
+}
+
+// TelescopingTerm returns 1/k - 1/(k+1), which is 1/(k(k+1)). Summing these
+// terms telescopes, so the sum for k from 1 to n is 1 - 1/(n+1).
+// TelescopingTerm returns ErrDivByZero if k is 0 or -1, and ErrDenOverflow if
+// k(k+1) does not fit.
+func TelescopingTerm(k int64) (N, error) {
+	if k == 0 || k == -1 {
+		return N{}, ErrDivByZero
+	} else if k == math.MaxInt64 {
+		return N{}, ErrDenOverflow
+	}
+	// k(k+1) is positive for all other k, so it is |k|*|k+1|; the absolute
+	// values are taken as uint64 so that k == math.MinInt64 works too
+	a, b := uint64(k), uint64(k+1)
+	if k < 0 {
+		a, b = -a, -b
+	}
+	hi, lo := bits.Mul64(a, b)
+	if hi != 0 || lo > math.MaxInt64 {
+		return N{}, ErrDenOverflow
+	}
+	return N{1, int64(lo) - 1}, nil
+}
@@ -0,0 +1,131 @@
+package rat128_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/kbolino/rat128"
+)
+
+func TestHarmonicNumber(t *testing.T) {
+	cases := []struct {
+		N   int
+		H   rat128.N
+		Err error
+	}{
+		{0, New(0, 1), nil},
+		{1, New(1, 1), nil},
+		{2, New(3, 2), nil},
+		{3, New(11, 6), nil},
+		{10, New(7381, 2520), nil},
+		{46, New(5943339269060627227, 1345655451257488800), nil},
+		{47, Zero, rat128.ErrNumOverflow},
+		{1000, Zero, rat128.ErrNumOverflow},
+		{-1, Zero, rat128.ErrInvalid},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprint(c.N), func(t *testing.T) {
+			h, err := rat128.HarmonicNumber(c.N)
+			if !errors.Is(err, c.Err) {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if h != c.H {
+				t.Errorf("got %v, want %v", h, c.H)
+			}
+		})
+	}
+}
+
+func TestBernoulli(t *testing.T) {
+	cases := []struct {
+		N  int
+		B  rat128.N
+		OK bool
+	}{
+		{0, New(1, 1), true},
+		{1, New(-1, 2), true},
+		{2, New(1, 6), true},
+		{3, New(0, 1), true},
+		{4, New(-1, 30), true},
+		{12, New(-691, 2730), true},
+		{33, New(0, 1), true},
+		{34, New(2577687858367, 6), true},
+		{35, New(0, 1), true},
+		{36, Zero, false},
+		{-1, Zero, false},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprint(c.N), func(t *testing.T) {
+			b, ok := rat128.Bernoulli(c.N)
+			if b != c.B || ok != c.OK {
+				t.Errorf("got (%v, %t), want (%v, %t)", b, ok, c.B, c.OK)
+			}
+		})
+	}
+	// the table satisfies the recurrence sum_{k=0}^{m} C(m+1, k) B_k == 0
+	for m := 1; m <= 34; m++ {
+		sum := new(big.Rat)
+		c := big.NewInt(1)
+		for k := 0; k <= m; k++ {
+			b, ok := rat128.Bernoulli(k)
+			if !ok {
+				t.Fatalf("B%d does not fit", k)
+			} else if err := rat128.CheckInvariants(b); err != nil {
+				t.Fatalf("B%d: %v", k, err)
+			}
+			sum.Add(sum, new(big.Rat).Mul(new(big.Rat).SetInt(c), b.BigRat()))
+			c.Mul(c, big.NewInt(int64(m+1-k)))
+			c.Quo(c, big.NewInt(int64(k+1)))
+		}
+		if sum.Sign() != 0 {
+			t.Errorf("recurrence for m=%d sums to %v", m, sum)
+		}
+	}
+}
+
+func TestTelescopingTerm(t *testing.T) {
+	const S = 3037000499 // floor(sqrt(math.MaxInt64))
+	cases := []struct {
+		K   int64
+		X   rat128.N
+		Err error
+	}{
+		{1, New(1, 2), nil},
+		{2, New(1, 6), nil},
+		{9, New(1, 90), nil},
+		{-2, New(1, 2), nil},
+		{-10, New(1, 90), nil},
+		{S, New(1, S*(S+1)), nil},
+		{S + 1, Zero, rat128.ErrDenOverflow},
+		{-S - 1, New(1, S*(S+1)), nil},
+		{-S - 2, Zero, rat128.ErrDenOverflow},
+		{math.MaxInt64, Zero, rat128.ErrDenOverflow},
+		{math.MinInt64, Zero, rat128.ErrDenOverflow},
+		{0, Zero, rat128.ErrDivByZero},
+		{-1, Zero, rat128.ErrDivByZero},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprint(c.K), func(t *testing.T) {
+			x, err := rat128.TelescopingTerm(c.K)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if x != c.X {
+				t.Errorf("got %v, want %v", x, c.X)
+			}
+		})
+	}
+	// the partial sums collapse to 1 - 1/(n+1)
+	var sum rat128.N
+	for k := int64(1); k <= 100; k++ {
+		x, err := rat128.TelescopingTerm(k)
+		if err != nil {
+			t.Fatalf("%d: got unexpected error %v", k, err)
+		}
+		sum = sum.Add(x)
+		if want := New(k, k+1); sum != want {
+			t.Fatalf("sum to %d: got %v, want %v", k, sum, want)
+		}
+	}
+}
@@ -0,0 +1,366 @@
+package rat128
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"sort"
+)
+
+// CumulativeSum returns the prefix sums of xs, such that out[i] is the sum of
+// xs[0] through xs[i] inclusive. Each sum is exact: if adding an element with
+// TryAdd overflows, that step is retried with big.Rat.
+// If a prefix sum does not fit, CumulativeSum returns the sums computed so
+// far along with the error, so len(out) is the index of the element that
+// overflowed.
+func CumulativeSum(xs []N) ([]N, error) {
+	out := make([]N, 0, len(xs))
+	var sum N
+	for i, x := range xs {
+		next, err := sum.TryAdd(x)
+		if err != nil {
+			r := new(big.Rat).Add(sum.BigRat(), x.BigRat())
+			if next, err = FromBigRat(r); err != nil {
+				return out, fmt.Errorf("adding element %d: %w", i, err)
+			}
+		}
+		sum = next
+		out = append(out, sum)
+	}
+	return out, nil
+}
+
+// SnapTo returns the element of allowed which is nearest to x.
+// If two elements are equally near, the smaller one is returned.
+// SnapTo returns ErrEmpty if allowed is empty.
+func (x N) SnapTo(allowed []N) (N, error) {
+	if len(allowed) == 0 {
+		return N{}, ErrEmpty
+	}
+	best := allowed[0]
+	for _, c := range allowed[1:] {
+		if d := cmpDist(x, c, best); d < 0 || (d == 0 && c.Cmp(best) < 0) {
+			best = c
+		}
+	}
+	return best, nil
+}
+
+// cmpDist compares the distances |x-a| and |x-b| and returns -1, 0, or 1 in
+// the manner of N.Cmp. The distances are computed exactly, falling back on
+// big.Rat if they would overflow.
+func cmpDist(x, a, b N) int {
+	da, errA := x.TrySub(a)
+	db, errB := x.TrySub(b)
+	if errA == nil && errB == nil {
+		return da.Abs().Cmp(db.Abs())
+	}
+	xr := x.BigRat()
+	ra := new(big.Rat).Sub(xr, a.BigRat())
+	rb := new(big.Rat).Sub(xr, b.BigRat())
+	return ra.Abs(ra).Cmp(rb.Abs(rb))
+}
+
+// ParallelCombine returns the reciprocal of the sum of the reciprocals of xs,
+// 1/(1/xs[0] + 1/xs[1] + ...), as when combining resistors in parallel.
+// ParallelCombine returns ErrDivByZero if any element of xs is zero or if the
+// reciprocals sum to zero, and ErrEmpty if xs is empty.
+func ParallelCombine(xs []N) (N, error) {
+	if len(xs) == 0 {
+		return N{}, ErrEmpty
+	}
+	var sum N
+	for i, x := range xs {
+		inv, err := x.TryInv()
+		if err != nil {
+			return N{}, fmt.Errorf("inverting element %d: %w", i, err)
+		}
+		sum, err = sum.TryAdd(inv)
+		if err != nil {
+			return N{}, fmt.Errorf("adding element %d: %w", i, err)
+		}
+	}
+	return sum.TryInv()
+}
+
+// IsSorted returns true if xs is sorted in ascending order, allowing equal
+// adjacent elements.
+func IsSorted(xs []N) bool {
+	for i := 1; i < len(xs); i++ {
+		if xs[i-1].Cmp(xs[i]) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSortedDesc returns true if xs is sorted in descending order, allowing
+// equal adjacent elements.
+func IsSortedDesc(xs []N) bool {
+	for i := 1; i < len(xs); i++ {
+		if xs[i-1].Cmp(xs[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// SumExact returns the sum of xs. Unlike chaining TryAdd, the result does not
+// depend on the order of xs: if an intermediate sum overflows, SumExact
+// continues with a big.Int numerator and denominator, and only returns an
+// error if the final sum does not fit. The sum of an empty slice is zero.
+func SumExact(xs []N) (N, error) {
+	var sum N
+	for i, x := range xs {
+		next, err := sum.TryAdd(x)
+		if err != nil {
+			return FromBigRat(sumBig(sum, xs[i:]))
+		}
+		sum = next
+	}
+	return sum, nil
+}
+
+// sumBig returns sum plus the sum of xs, accumulating in big.Int.
+func sumBig(sum N, xs []N) *big.Rat {
+	num, den := big.NewInt(sum.Num()), big.NewInt(sum.Den())
+	var m, d, t, g big.Int
+	for _, x := range xs {
+		m.SetInt64(x.Num())
+		d.SetInt64(x.Den())
+		// num/den + m/d = (num*d + m*den) / (den*d)
+		num.Mul(num, &d)
+		num.Add(num, t.Mul(&m, den))
+		den.Mul(den, &d)
+		if den.BitLen() > 512 {
+			g.GCD(nil, nil, t.Abs(num), den)
+			num.Quo(num, &g)
+			den.Quo(den, &g)
+		}
+	}
+	return new(big.Rat).SetFrac(num, den)
+}
+
+// SumOrBig returns the sum of xs, computed exactly as by SumExact, as an N
+// and a nil big.Rat if it fits, or else as a zero N and a new big.Rat.
+// Thus, the exact sum is always available in one form or the other.
+func SumOrBig(xs []N) (N, *big.Rat) {
+	var sum N
+	for i, x := range xs {
+		next, err := sum.TryAdd(x)
+		if err != nil {
+			r := sumBig(sum, xs[i:])
+			if z, err := FromBigRat(r); err == nil {
+				return z, nil
+			}
+			return N{}, r
+		}
+		sum = next
+	}
+	return sum, nil
+}
+
+// Dot returns the dot product of xs and ys, the sum of xs[i]*ys[i].
+// Like SumExact, intermediate products and sums that overflow are carried in
+// big.Rat, so Dot only returns an error if the final result does not fit.
+// Dot returns ErrInvalid if xs and ys have different lengths.
+func Dot(xs, ys []N) (N, error) {
+	if len(xs) != len(ys) {
+		return N{}, ErrInvalid
+	}
+	var sum N
+	for i := range xs {
+		p, err := xs[i].TryMul(ys[i])
+		if err == nil {
+			p, err = sum.TryAdd(p)
+		}
+		if err != nil {
+			return dotBig(sum, xs[i:], ys[i:])
+		}
+		sum = p
+	}
+	return sum, nil
+}
+
+// dotBig returns sum plus the dot product of xs and ys, accumulating in
+// big.Rat.
+func dotBig(sum N, xs, ys []N) (N, error) {
+	acc := sum.BigRat()
+	var p big.Rat
+	for i := range xs {
+		acc.Add(acc, p.Mul(xs[i].BigRat(), ys[i].BigRat()))
+	}
+	return FromBigRat(acc)
+}
+
+// ScaleSlice returns a new slice with each element of xs multiplied by factor.
+// If a product overflows, ScaleSlice returns nil and the wrapped error.
+func ScaleSlice(xs []N, factor N) ([]N, error) {
+	out := make([]N, len(xs))
+	for i, x := range xs {
+		var err error
+		if out[i], err = x.TryMul(factor); err != nil {
+			return nil, fmt.Errorf("scaling element %d: %w", i, err)
+		}
+	}
+	return out, nil
+}
+
+// NormalizeToUnit returns a new slice with each element of xs divided by the
+// sum of xs, so that the elements of the result sum to exactly 1.
+// The sum is computed with SumExact. NormalizeToUnit returns ErrDivByZero if
+// the sum is zero, including when xs is empty.
+func NormalizeToUnit(xs []N) ([]N, error) {
+	total, err := SumExact(xs)
+	if err != nil {
+		return nil, fmt.Errorf("computing total: %w", err)
+	}
+	if total.IsZero() {
+		return nil, ErrDivByZero
+	}
+	out := make([]N, len(xs))
+	for i, x := range xs {
+		if out[i], err = x.TryDiv(total); err != nil {
+			return nil, fmt.Errorf("dividing element %d: %w", i, err)
+		}
+	}
+	return out, nil
+}
+
+// Dedup returns a new slice containing the distinct elements of xs in
+// ascending order. The input slice is not modified.
+func Dedup(xs []N) []N {
+	out := make([]N, len(xs))
+	copy(out, xs)
+	sort.Slice(out, func(i, j int) bool { return out[i].Cmp(out[j]) < 0 })
+	// since valid values are reduced, equal values are identical
+	k := 0
+	for i, x := range out {
+		if i == 0 || x != out[k-1] {
+			out[k] = x
+			k++
+		}
+	}
+	return out[:k]
+}
+
+// SortedUnion returns the elements that are in xs, ys, or both, in ascending
+// order without duplicates. Both xs and ys must already be sorted in
+// ascending order, as by Dedup, though they may contain duplicates.
+func SortedUnion(xs, ys []N) []N {
+	out := make([]N, 0, len(xs)+len(ys))
+	i, j := 0, 0
+	for i < len(xs) || j < len(ys) {
+		var x N
+		switch {
+		case j == len(ys):
+			x = xs[i]
+		case i == len(xs):
+			x = ys[j]
+		case xs[i].Cmp(ys[j]) <= 0:
+			x = xs[i]
+		default:
+			x = ys[j]
+		}
+		for i < len(xs) && xs[i] == x {
+			i++
+		}
+		for j < len(ys) && ys[j] == x {
+			j++
+		}
+		out = append(out, x)
+	}
+	return out
+}
+
+// SortedIntersect returns the elements that are in both xs and ys, in
+// ascending order without duplicates. Both xs and ys must already be sorted
+// in ascending order, as by Dedup, though they may contain duplicates.
+func SortedIntersect(xs, ys []N) []N {
+	var out []N
+	i, j := 0, 0
+	for i < len(xs) && j < len(ys) {
+		switch c := xs[i].Cmp(ys[j]); {
+		case c < 0:
+			i++
+		case c > 0:
+			j++
+		default:
+			if len(out) == 0 || out[len(out)-1] != xs[i] {
+				out = append(out, xs[i])
+			}
+			i++
+			j++
+		}
+	}
+	return out
+}
+
+// BucketIndex returns the index i of the bucket containing x, such that
+// edges[i] <= x < edges[i+1], given bucket edges sorted in ascending order.
+// BucketIndex returns -1 if x is below the first edge, and len(edges)-1 if x
+// is at or above the last edge.
+func (x N) BucketIndex(edges []N) int {
+	return sort.Search(len(edges), func(i int) bool { return edges[i].Cmp(x) > 0 }) - 1
+}
+
+// MinMax tracks the minimum and maximum of a stream of values without
+// storing them. The zero value is ready to use and has observed nothing.
+type MinMax struct {
+	min, max N
+	ok       bool
+}
+
+// Observe updates the minimum and maximum with x.
+func (mm *MinMax) Observe(x N) {
+	if !mm.ok {
+		mm.min, mm.max, mm.ok = x, x, true
+		return
+	}
+	if x.Cmp(mm.min) < 0 {
+		mm.min = x
+	} else if x.Cmp(mm.max) > 0 {
+		mm.max = x
+	}
+}
+
+// Min returns the smallest value observed so far and true, or 0 and false if
+// no values have been observed.
+func (mm *MinMax) Min() (N, bool) {
+	return mm.min, mm.ok
+}
+
+// Max returns the largest value observed so far and true, or 0 and false if
+// no values have been observed.
+func (mm *MinMax) Max() (N, bool) {
+	return mm.max, mm.ok
+}
+
+// IntegerScaling returns the smallest positive integer k such that k*xs[i]
+// is an integer for every i, which is the least common multiple of the
+// denominators, along with those integers.
+// IntegerScaling returns ErrDenOverflow if k does not fit in int64, and
+// ErrNumOverflow, identifying the element, if a scaled value does not fit.
+func IntegerScaling(xs []N) (int64, []int64, error) {
+	k := int64(1)
+	for i, x := range xs {
+		n := x.Den()
+		// lcm(k, n) = k/gcd(k, n)*n
+		hi, lo := bits.Mul64(uint64(k/GCD(k, n)), uint64(n))
+		if hi != 0 || lo > math.MaxInt64 {
+			return 0, nil, fmt.Errorf("scaling element %d: %w", i, ErrDenOverflow)
+		}
+		k = int64(lo)
+	}
+	out := make([]int64, len(xs))
+	for i, x := range xs {
+		// k/Den() is exact, so only the product can overflow
+		v, ok := addMul64(0, x.Num(), k/x.Den())
+		if !ok || v == math.MinInt64 {
+			return 0, nil, fmt.Errorf("scaling element %d: %w", i, ErrNumOverflow)
+		}
+		out[i] = v
+	}
+	return k, out, nil
+}
@@ -0,0 +1,435 @@
+package rat128_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/kbolino/rat128"
+)
+
+func TestCumulativeSum(t *testing.T) {
+	cases := []struct {
+		Xs, Out []rat128.N
+		Err     error
+	}{
+		{nil, []rat128.N{}, nil},
+		{[]rat128.N{New(1, 2)}, []rat128.N{New(1, 2)}, nil},
+		{
+			[]rat128.N{New(1, 2), New(1, 3), New(1, 6), New(-1, 1)},
+			[]rat128.N{New(1, 2), New(5, 6), New(1, 1), New(0, 1)},
+			nil,
+		},
+		{
+			[]rat128.N{New(math.MaxInt64-1, 1), New(1, 1), New(1, 1), New(-1, 1)},
+			[]rat128.N{New(math.MaxInt64-1, 1), New(math.MaxInt64, 1)},
+			rat128.ErrNumOverflow,
+		},
+		{
+			[]rat128.N{New(1426217253, 4), New(1089109167714302979, 30352181740), New(1, 1)},
+			[]rat128.N{New(1426217253, 4), New(5955655241829596517, 15176090870), New(5955655241829596517+15176090870, 15176090870)},
+			nil,
+		},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprint(c.Xs), func(t *testing.T) {
+			out, err := rat128.CumulativeSum(c.Xs)
+			if !errors.Is(err, c.Err) {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			}
+			if len(out) != len(c.Out) {
+				t.Fatalf("got %v, want %v", out, c.Out)
+			}
+			for i := range out {
+				if out[i] != c.Out[i] {
+					t.Errorf("got %v, want %v", out, c.Out)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestN_SnapTo(t *testing.T) {
+	snaps := []rat128.N{New(0, 1), New(1, 4), New(1, 3), New(1, 2), New(2, 3), New(3, 4), New(1, 1)}
+	cases := []struct {
+		X       rat128.N
+		Allowed []rat128.N
+		Z       rat128.N
+		Err     error
+	}{
+		{New(1, 2), nil, Zero, rat128.ErrEmpty},
+		{New(1, 2), snaps, New(1, 2), nil},
+		{New(-1, 2), snaps, New(0, 1), nil},
+		{New(2, 1), snaps, New(1, 1), nil},
+		{New(3, 10), snaps, New(1, 3), nil},
+		{New(7, 24), snaps, New(1, 4), nil}, // tie between 1/4 and 1/3
+		{New(7, 10), snaps, New(2, 3), nil},
+		{New(9, 10), snaps, New(1, 1), nil},
+		{New(0, 1), []rat128.N{New(math.MaxInt64, 1), New(-math.MaxInt64, 1)}, New(-math.MaxInt64, 1), nil},
+		{New(math.MaxInt64, 1), []rat128.N{New(-math.MaxInt64, 1), New(1, 1)}, New(1, 1), nil},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s)", c.X.RationalString("_")), func(t *testing.T) {
+			z, err := c.X.SnapTo(c.Allowed)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if c.Err == nil && z != c.Z {
+				t.Errorf("got %v, want %v", z, c.Z)
+			}
+		})
+	}
+}
+
+func TestParallelCombine(t *testing.T) {
+	cases := []struct {
+		Xs  []rat128.N
+		Z   rat128.N
+		Err error
+	}{
+		{nil, Zero, rat128.ErrEmpty},
+		{[]rat128.N{New(5, 1)}, New(5, 1), nil},
+		{[]rat128.N{New(2, 1), New(2, 1)}, New(1, 1), nil},
+		{[]rat128.N{New(1, 1), New(2, 1), New(3, 1)}, New(6, 11), nil},
+		{[]rat128.N{New(1, 2), New(1, 3)}, New(1, 5), nil},
+		{[]rat128.N{New(1, 1), New(-1, 1)}, Zero, rat128.ErrDivByZero},
+		{[]rat128.N{New(1, 1), New(0, 1)}, Zero, rat128.ErrDivByZero},
+		{[]rat128.N{New(1, math.MaxInt64), New(1, 1)}, Zero, rat128.ErrNumOverflow},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprint(c.Xs), func(t *testing.T) {
+			z, err := rat128.ParallelCombine(c.Xs)
+			if !errors.Is(err, c.Err) {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if c.Err == nil && z != c.Z {
+				t.Errorf("got %v, want %v", z, c.Z)
+			}
+		})
+	}
+}
+
+func TestIsSorted(t *testing.T) {
+	cases := []struct {
+		Xs        []rat128.N
+		Asc, Desc bool
+	}{
+		{nil, true, true},
+		{[]rat128.N{New(1, 2)}, true, true},
+		{[]rat128.N{New(1, 3), New(1, 2)}, true, false},
+		{[]rat128.N{New(1, 2), New(1, 3)}, false, true},
+		{[]rat128.N{New(1, 2), New(1, 2)}, true, true},
+		{[]rat128.N{New(-1, 2), New(0, 1), New(0, 1), New(1, 3), New(1, 2)}, true, false},
+		{[]rat128.N{New(-1, 2), New(1, 3), New(0, 1)}, false, false},
+		{[]rat128.N{New(-math.MaxInt64, 1), New(math.MaxInt64, 1)}, true, false},
+		{[]rat128.N{New(math.MaxInt64, 1), New(-math.MaxInt64, 1)}, false, true},
+		{[]rat128.N{New(1, math.MaxInt64), New(1, math.MaxInt64-1)}, true, false},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprint(c.Xs), func(t *testing.T) {
+			if r := rat128.IsSorted(c.Xs); r != c.Asc {
+				t.Errorf("got IsSorted()=%v, want %v", r, c.Asc)
+			}
+			if r := rat128.IsSortedDesc(c.Xs); r != c.Desc {
+				t.Errorf("got IsSortedDesc()=%v, want %v", r, c.Desc)
+			}
+		})
+	}
+}
+
+func TestSumExact(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		Name string
+		Xs   []rat128.N
+		Sum  rat128.N
+		Err  error
+	}{
+		{"Empty", nil, Zero, nil},
+		{"Small", []rat128.N{New(1, 2), New(1, 3), New(1, 6)}, New(1, 1), nil},
+		{"NumCancels", []rat128.N{New(M, 1), New(M, 1), New(-M, 1)}, New(M, 1), nil},
+		{"DenCancels", []rat128.N{New(1, P1), New(1, P2), New(-1, P1), New(-1, P2), New(1, 3)}, New(1, 3), nil},
+		{"NumOverflow", []rat128.N{New(M, 1), New(1, 1)}, Zero, rat128.ErrNumOverflow},
+		{"DenOverflow", []rat128.N{New(1, P1*P2), New(1, P3*P4)}, Zero, rat128.ErrDenOverflow},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			sum, err := rat128.SumExact(c.Xs)
+			if !errors.Is(err, c.Err) {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if c.Err == nil && sum != c.Sum {
+				t.Errorf("got %v, want %v", sum, c.Sum)
+			}
+		})
+	}
+}
+
+func TestDot(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		Name   string
+		Xs, Ys []rat128.N
+		Dot    rat128.N
+		Err    error
+	}{
+		{"Empty", nil, nil, Zero, nil},
+		{"Small", []rat128.N{New(1, 2), New(2, 3)}, []rat128.N{New(2, 1), New(3, 4)}, New(3, 2), nil},
+		{"Cancels", []rat128.N{New(M, 1), New(M, 1)}, []rat128.N{New(2, 1), New(-2, 1)}, Zero, nil},
+		{"Overflow", []rat128.N{New(M, 1)}, []rat128.N{New(2, 1)}, Zero, rat128.ErrNumOverflow},
+		{"Mismatch", []rat128.N{New(1, 1)}, nil, Zero, rat128.ErrInvalid},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			dot, err := rat128.Dot(c.Xs, c.Ys)
+			if !errors.Is(err, c.Err) {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if c.Err == nil && dot != c.Dot {
+				t.Errorf("got %v, want %v", dot, c.Dot)
+			}
+		})
+	}
+}
+
+func TestScaleSlice(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		Xs     []rat128.N
+		Factor rat128.N
+		Out    []rat128.N
+		Err    error
+	}{
+		{nil, New(2, 1), []rat128.N{}, nil},
+		{[]rat128.N{New(1, 2), New(-2, 3), Zero}, New(3, 4), []rat128.N{New(3, 8), New(-1, 2), Zero}, nil},
+		{[]rat128.N{New(1, 1), New(M, 1)}, New(2, 1), nil, rat128.ErrNumOverflow},
+		{[]rat128.N{New(1, M)}, New(1, 2), nil, rat128.ErrDenOverflow},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%v*%v", c.Xs, c.Factor), func(t *testing.T) {
+			out, err := rat128.ScaleSlice(c.Xs, c.Factor)
+			if !errors.Is(err, c.Err) {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if !reflect.DeepEqual(out, c.Out) {
+				t.Errorf("got %v, want %v", out, c.Out)
+			}
+		})
+	}
+}
+
+func TestNormalizeToUnit(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		Xs  []rat128.N
+		Out []rat128.N
+		Err error
+	}{
+		{[]rat128.N{New(1, 1), New(2, 1), New(3, 1)}, []rat128.N{New(1, 6), New(1, 3), New(1, 2)}, nil},
+		{[]rat128.N{New(1, 2), New(1, 3)}, []rat128.N{New(3, 5), New(2, 5)}, nil},
+		{[]rat128.N{New(5, 7)}, []rat128.N{New(1, 1)}, nil},
+		{[]rat128.N{New(3, 1), New(-1, 1)}, []rat128.N{New(3, 2), New(-1, 2)}, nil},
+		{[]rat128.N{New(M, 1), New(M, 1), New(-M, 1)}, []rat128.N{New(1, 1), New(1, 1), New(-1, 1)}, nil},
+		{nil, nil, rat128.ErrDivByZero},
+		{[]rat128.N{New(1, 2), New(-1, 2)}, nil, rat128.ErrDivByZero},
+		{[]rat128.N{New(M, 1), New(1, 1)}, nil, rat128.ErrNumOverflow},
+		{[]rat128.N{New(1, P1*P2), New(1, P3*P4)}, nil, rat128.ErrDenOverflow},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%v", c.Xs), func(t *testing.T) {
+			out, err := rat128.NormalizeToUnit(c.Xs)
+			if !errors.Is(err, c.Err) {
+				t.Errorf("got error %v, want %v", err, c.Err)
+				return
+			} else if !reflect.DeepEqual(out, c.Out) {
+				t.Errorf("got %v, want %v", out, c.Out)
+			}
+			if c.Err == nil {
+				if sum, err := rat128.SumExact(out); err != nil || sum != New(1, 1) {
+					t.Errorf("sum: got (%v, %v), want (1/1, nil)", sum, err)
+				}
+			}
+		})
+	}
+}
+
+func TestDedup(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		Xs, Out []rat128.N
+	}{
+		{nil, []rat128.N{}},
+		{[]rat128.N{New(1, 2)}, []rat128.N{New(1, 2)}},
+		{
+			[]rat128.N{New(1, 2), New(-1, 3), New(2, 4), New(M, M-1), New(M-1, M-2), New(-1, 3)},
+			[]rat128.N{New(-1, 3), New(1, 2), New(M, M-1), New(M-1, M-2)},
+		},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprint(c.Xs), func(t *testing.T) {
+			in := append([]rat128.N(nil), c.Xs...)
+			out := rat128.Dedup(in)
+			if !reflect.DeepEqual(out, c.Out) {
+				t.Errorf("got %v, want %v", out, c.Out)
+			}
+			if !reflect.DeepEqual(in, c.Xs) {
+				t.Errorf("input modified: got %v, want %v", in, c.Xs)
+			}
+		})
+	}
+}
+
+func TestSortedUnionIntersect(t *testing.T) {
+	cases := []struct {
+		Xs, Ys           []rat128.N
+		Union, Intersect []rat128.N
+	}{
+		{nil, nil, []rat128.N{}, nil},
+		{[]rat128.N{New(1, 2)}, nil, []rat128.N{New(1, 2)}, nil},
+		{nil, []rat128.N{New(1, 2)}, []rat128.N{New(1, 2)}, nil},
+		{
+			[]rat128.N{New(-1, 1), New(1, 3), New(1, 2), New(1, 2)},
+			[]rat128.N{New(1, 4), New(1, 2), New(2, 3)},
+			[]rat128.N{New(-1, 1), New(1, 4), New(1, 3), New(1, 2), New(2, 3)},
+			[]rat128.N{New(1, 2)},
+		},
+		{
+			[]rat128.N{New(1, 3), New(2, 3)},
+			[]rat128.N{New(1, 3), New(1, 3), New(2, 3)},
+			[]rat128.N{New(1, 3), New(2, 3)},
+			[]rat128.N{New(1, 3), New(2, 3)},
+		},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprint(c.Xs, c.Ys), func(t *testing.T) {
+			if u := rat128.SortedUnion(c.Xs, c.Ys); !reflect.DeepEqual(u, c.Union) {
+				t.Errorf("union: got %v, want %v", u, c.Union)
+			}
+			if i := rat128.SortedIntersect(c.Xs, c.Ys); !reflect.DeepEqual(i, c.Intersect) {
+				t.Errorf("intersect: got %v, want %v", i, c.Intersect)
+			}
+		})
+	}
+}
+
+func TestN_BucketIndex(t *testing.T) {
+	const M = math.MaxInt64
+	edges := []rat128.N{New(-M, 1), New(-1, 2), New(0, 1), New(M-1, M), New(1, 1), New(M, 1)}
+	cases := []struct {
+		X     rat128.N
+		Edges []rat128.N
+		I     int
+	}{
+		{New(0, 1), nil, -1},
+		{New(-M, 1), edges, 0},
+		{New(-1, 1), edges, 0},
+		{New(-1, 2), edges, 1},
+		{New(-1, M), edges, 1},
+		{New(0, 1), edges, 2},
+		{New(M-2, M-1), edges, 2},
+		{New(M-1, M), edges, 3},
+		{New(1, 1), edges, 4},
+		{New(M, M-1), edges, 4},
+		{New(M, 1), edges, 5},
+		{New(-1, 1), edges[1:], -1},
+		{New(1, 2), []rat128.N{New(1, 2), New(1, 2), New(1, 1)}, 1},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s)in%v", c.X.RationalString("_"), c.Edges), func(t *testing.T) {
+			if i := c.X.BucketIndex(c.Edges); i != c.I {
+				t.Errorf("got %d, want %d", i, c.I)
+			}
+		})
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	const M = math.MaxInt64
+	var mm rat128.MinMax
+	if x, ok := mm.Min(); ok || x != Zero {
+		t.Errorf("got Min() = (%v, %t) before observing, want (0/1, false)", x, ok)
+	}
+	if x, ok := mm.Max(); ok || x != Zero {
+		t.Errorf("got Max() = (%v, %t) before observing, want (0/1, false)", x, ok)
+	}
+	steps := []struct {
+		X        rat128.N
+		Min, Max rat128.N
+	}{
+		{New(1, 2), New(1, 2), New(1, 2)},
+		{New(1, 3), New(1, 3), New(1, 2)},
+		{New(2, 3), New(1, 3), New(2, 3)},
+		{New(1, 2), New(1, 3), New(2, 3)},
+		{New(M, M-1), New(1, 3), New(M, M-1)},
+		{New(M-1, M-2), New(1, 3), New(M-1, M-2)},
+		{New(-1, M), New(-1, M), New(M-1, M-2)},
+		{New(-1, M-1), New(-1, M-1), New(M-1, M-2)},
+	}
+	for i, s := range steps {
+		mm.Observe(s.X)
+		if x, ok := mm.Min(); !ok || x != s.Min {
+			t.Errorf("step %d: got Min() = (%v, %t), want (%v, true)", i, x, ok, s.Min)
+		}
+		if x, ok := mm.Max(); !ok || x != s.Max {
+			t.Errorf("step %d: got Max() = (%v, %t), want (%v, true)", i, x, ok, s.Max)
+		}
+	}
+}
+
+func TestSumOrBig(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		Name string
+		Xs   []rat128.N
+		Sum  rat128.N
+		Big  string
+	}{
+		{"Empty", nil, Zero, ""},
+		{"Small", []rat128.N{New(1, 2), New(1, 3)}, New(5, 6), ""},
+		{"Cancels", []rat128.N{New(M, 1), New(M, 1), New(-M, 1)}, New(M, 1), ""},
+		{"NumOverflow", []rat128.N{New(M, 1), New(M, 1)}, Zero, "18446744073709551614/1"},
+		{"DenOverflow", []rat128.N{New(1, P1*P2), New(1, P3*P4)}, Zero, fmt.Sprintf("%d/%d", P1*P2+P3*P4, new(big.Int).Mul(big.NewInt(P1*P2), big.NewInt(P3*P4)))},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			sum, r := rat128.SumOrBig(c.Xs)
+			if sum != c.Sum {
+				t.Errorf("got %v, want %v", sum, c.Sum)
+			}
+			if c.Big == "" && r != nil {
+				t.Errorf("got big.Rat %v, want nil", r)
+			} else if c.Big != "" && (r == nil || r.String() != c.Big) {
+				t.Errorf("got big.Rat %v, want %s", r, c.Big)
+			}
+		})
+	}
+}
+
+func TestIntegerScaling(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		Name string
+		Xs   []rat128.N
+		K    int64
+		Ints []int64
+		Err  error
+	}{
+		{"Empty", nil, 1, []int64{}, nil},
+		{"Integers", []rat128.N{New(2, 1), New(-3, 1)}, 1, []int64{2, -3}, nil},
+		{"Stoichiometry", []rat128.N{New(1, 1), New(3, 2), New(1, 3)}, 6, []int64{6, 9, 2}, nil},
+		{"SharedFactors", []rat128.N{New(1, 4), New(-5, 6), New(7, 10)}, 60, []int64{15, -50, 42}, nil},
+		{"Zero", []rat128.N{Zero, New(1, 7)}, 7, []int64{0, 1}, nil},
+		{"MaxDen", []rat128.N{New(1, M), New(M-1, M)}, M, []int64{1, M - 1}, nil},
+		{"DenOverflow", []rat128.N{New(1, P1*P2), New(1, P3*P4)}, 0, nil, rat128.ErrDenOverflow},
+		{"NumOverflow", []rat128.N{New(M, 1), New(1, 2)}, 0, nil, rat128.ErrNumOverflow},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			k, ints, err := rat128.IntegerScaling(c.Xs)
+			if !errors.Is(err, c.Err) {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if k != c.K || !reflect.DeepEqual(ints, c.Ints) {
+				t.Errorf("got (%d, %v), want (%d, %v)", k, ints, c.K, c.Ints)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package rat128
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimecodeString interprets x as a number of seconds and returns it as a
+// timecode in the form "HH:MM:SS:FF", where FF is the frame number within
+// the second at the given frame rate, rounded to nearest with ties away from
+// zero. If rounding the frame number reaches fps, it carries over into the
+// seconds. Hours are not limited to two digits. If x is negative, the
+// timecode is preceded by a negative sign.
+// TimecodeString returns ErrInvalid if fps is not positive, and an overflow
+// error if the frame number cannot be computed.
+func (x N) TimecodeString(fps N) (string, error) {
+	if fps.Sign() <= 0 {
+		return "", ErrInvalid
+	}
+	sign := ""
+	if x.Sign() < 0 {
+		sign = "-"
+	}
+	m, n := abs64(x.Num()), x.Den()
+	secs := m / n
+	frac, err := Try(m%n, n)
+	if err != nil {
+		return "", err
+	}
+	frames, err := frac.TryMul(fps)
+	if err != nil {
+		return "", err
+	}
+	frames = frames.Round(RoundHalfAwayFromZero)
+	if frames.Cmp(fps) >= 0 {
+		secs++
+		frames = N{}
+	}
+	h, mm, ss := secs/3600, secs/60%60, secs%60
+	return fmt.Sprintf("%s%02d:%02d:%02d:%02d", sign, h, mm, ss, frames.Num()), nil
+}
+
+// FromDenominationCounts returns the total value of a collection of coins or
+// notes, where each key of counts is a denomination in minor units (such as
+// cents) and each value is how many of that denomination there are. The
+// result is in major units, so a denomination of 25 is worth 1/4.
+// The total is computed exactly regardless of the order in which the map is
+// visited, and FromDenominationCounts returns ErrNumOverflow only if the
+// total itself does not fit.
+func FromDenominationCounts(counts map[int64]int64) (N, error) {
+	total := new(big.Int)
+	var t big.Int
+	for denom, count := range counts {
+		total.Add(total, t.Mul(big.NewInt(denom), big.NewInt(count)))
+	}
+	return FromBigRat(new(big.Rat).SetFrac(total, big.NewInt(100)))
+}
+
+// ToByteChannel interprets x as a color channel intensity in [0, 1] and
+// returns it scaled to [0, 255] and rounded to an integer according to mode.
+// If x is outside of [0, 1], it is clamped to that interval first and the
+// second result is false.
+func (x N) ToByteChannel(mode RoundingMode) (uint8, bool) {
+	if c := x.ClampUnit(); c != x {
+		return uint8(c.m * 255), false
+	}
+	// x*255 <= 255, but the product of the numerator and 255 may not fit in
+	// 64 bits, so divide with 128-bit precision
+	n := uint64(x.Den())
+	hi, lo := bits.Mul64(uint64(x.m), 255)
+	q, r := bits.Div64(hi, lo, n)
+	if r != 0 {
+		// r < n < 2^63, so 2*r can't overflow
+		var half int
+		switch r2 := r * 2; {
+		case r2 < n:
+			half = -1
+		case r2 > n:
+			half = 1
+		}
+		if mode.roundsAway(false, q%2 != 0, half) {
+			q++
+		}
+	}
+	return uint8(q), true
+}
+
+// FromByteChannel returns the color channel intensity v/255, the inverse of
+// ToByteChannel.
+func FromByteChannel(v uint8) N {
+	x, _ := Try(int64(v), 255)
+	return x
+}
+
+// FromPPM returns the fraction represented by the given number of parts per
+// million, ppm/1000000.
+// FromPPM panics if the result would overflow, which can only happen if the
+// denominator of ppm is very large; ppm.TryDivInt64(1000000) is equivalent
+// but returns an error instead.
+func FromPPM(ppm N) N {
+	return ppm.DivInt64(1000000)
+}
+
+// ToPPM returns x in parts per million, x*1000000.
+// ToPPM returns an overflow error if the result does not fit.
+func (x N) ToPPM() (N, error) {
+	return x.TryMulInt64(1000000)
+}
+
+// ParseAspectRatioString parses an aspect ratio in the form "w:h", where w
+// and h are positive integers in base 10, such as "16:9". The result is w/h
+// in lowest terms, so "1920:1080" is also 16/9.
+// ParseAspectRatioString returns ErrFmtInvalid if s is not in that form.
+func ParseAspectRatioString(s string) (N, error) {
+	w, h, ok := strings.Cut(s, ":")
+	if !ok {
+		return N{}, ErrFmtInvalid
+	}
+	wi, err := parsePositiveInt(w)
+	if err != nil {
+		return N{}, err
+	}
+	hi, err := parsePositiveInt(h)
+	if err != nil {
+		return N{}, err
+	}
+	return Try(wi, hi)
+}
+
+// parsePositiveInt parses s as a positive integer in base 10 without a sign,
+// returning ErrFmtInvalid if it is not one.
+func parsePositiveInt(s string) (int64, error) {
+	if s == "" || s[0] < '0' || s[0] > '9' {
+		return 0, ErrFmtInvalid
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || v == 0 {
+		return 0, ErrFmtInvalid
+	}
+	return v, nil
+}
+
+// AspectRatioString returns a string representation of x as an aspect ratio
+// in lowest terms, as "w:h". This is meaningful only if x is positive.
+func (x N) AspectRatioString() string {
+	return x.RationalString(":")
+}
+
+// QuantizeToGrid returns x rounded to the nearest multiple of 1/division,
+// with ties away from zero, such as for snapping a position in beats to a
+// sequencer grid with the given number of steps per beat.
+// QuantizeToGrid returns ErrDenInvalid if division is not positive, and an
+// overflow error if x*division does not fit.
+func (x N) QuantizeToGrid(division int64) (N, error) {
+	if division <= 0 {
+		return N{}, ErrDenInvalid
+	}
+	return x.RoundToMultiple(N{1, division - 1}, RoundHalfAwayFromZero)
+}
+
+// GridIndex returns the index k such that x == k/division and true, if x lies
+// exactly on the grid with the given number of steps per unit. Otherwise, or
+// if division is not positive, it returns 0 and false. It is the same as
+// AsRatioOf, under a name that reads better alongside QuantizeToGrid.
+func (x N) GridIndex(division int64) (int64, bool) {
+	return x.AsRatioOf(division)
+}
+
+// RatioOfDurations returns the exact ratio a/b of two durations in lowest
+// terms, so that 500*time.Millisecond over time.Second is 1/2.
+// RatioOfDurations returns ErrDivByZero if b is zero, and an overflow error
+// if the ratio does not fit, which can only happen if a or b is the minimum
+// time.Duration.
+func RatioOfDurations(a, b time.Duration) (N, error) {
+	if b == 0 {
+		return N{}, ErrDivByZero
+	} else if a == math.MinInt64 || b == math.MinInt64 {
+		return FromBigRat(new(big.Rat).SetFrac(big.NewInt(int64(a)), big.NewInt(int64(b))))
+	}
+	return N{int64(a), 0}.TryDiv(N{int64(b), 0})
+}
+
+// FromFeetInches returns the length in feet of the given number of feet,
+// inches, and sixteenths of an inch, which is
+// feet + inches/12 + sixteenths/192. The parts need not be normalized or have
+// the same sign, so FromFeetInches(0, 15, -8) is 29/24, which is 1' 2 1/2".
+// FromFeetInches panics if the result would overflow.
+func FromFeetInches(feet, inches, sixteenths int64) N {
+	var t big.Int
+	total := new(big.Int).Mul(big.NewInt(feet), big.NewInt(192))
+	total.Add(total, t.Mul(big.NewInt(inches), big.NewInt(16)))
+	total.Add(total, big.NewInt(sixteenths))
+	x, err := FromBigRat(new(big.Rat).SetFrac(total, big.NewInt(192)))
+	if err != nil {
+		panic(err)
+	}
+	return x
+}
+
+// FeetInchesString interprets x as a length in feet and returns it as feet,
+// inches, and a reduced fraction of an inch, such as 5' 3 1/2" for 5 7/24.
+// The fraction is omitted if it is zero, but the inches are always present.
+// If x is negative, the string is preceded by a negative sign.
+// FeetInchesString returns ErrInexact if x is not a multiple of 1/16 inch,
+// which is 1/192 foot.
+func (x N) FeetInchesString() (string, error) {
+	n := x.Den()
+	if 192%n != 0 {
+		return "", ErrInexact
+	}
+	m := abs64(x.Num())
+	// k is the fractional part of |x| in sixteenths of an inch, which is
+	// less than 192 and so can't overflow
+	feet, k := m/n, m%n*(192/n)
+	var buf strings.Builder
+	if x.m < 0 {
+		buf.WriteByte('-')
+	}
+	fmt.Fprintf(&buf, "%d' %d", feet, k/16)
+	if s := k % 16; s != 0 {
+		buf.WriteByte(' ')
+		buf.WriteString(New(s, 16).RationalString("/"))
+	}
+	buf.WriteByte('"')
+	return buf.String(), nil
+}
@@ -0,0 +1,345 @@
+package rat128_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/kbolino/rat128"
+)
+
+func TestN_TimecodeString(t *testing.T) {
+	ntsc := New(30000, 1001)
+	cases := []struct {
+		X, FPS rat128.N
+		String string
+		Err    error
+	}{
+		{New(0, 1), New(24, 1), "00:00:00:00", nil},
+		{New(1, 2), New(24, 1), "00:00:00:12", nil},
+		{New(3661, 1), New(24, 1), "01:01:01:00", nil},
+		{New(-3661, 1), New(24, 1), "-01:01:01:00", nil},
+		{New(7322, 2).Add(New(1, 3)), New(30, 1), "01:01:01:10", nil},
+		{New(59, 1).Add(New(99, 100)), New(24, 1), "00:01:00:00", nil},
+		{New(1, 48), New(24, 1), "00:00:00:01", nil},
+		{New(1, 49), New(24, 1), "00:00:00:00", nil},
+		{New(1001, 2000), ntsc, "00:00:00:15", nil},
+		{New(1, 1).Sub(New(1, 100)), ntsc, "00:00:01:00", nil},
+		{New(360000, 1), New(25, 1), "100:00:00:00", nil},
+		{New(1, 1), New(0, 1), "", rat128.ErrInvalid},
+		{New(1, 1), New(-24, 1), "", rat128.ErrInvalid},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s)@(%s)", c.X.RationalString("_"), c.FPS.RationalString("_")), func(t *testing.T) {
+			s, err := c.X.TimecodeString(c.FPS)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if s != c.String {
+				t.Errorf("got %q, want %q", s, c.String)
+			}
+		})
+	}
+}
+
+func TestFromDenominationCounts(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		Name   string
+		Counts map[int64]int64
+		X      rat128.N
+		Err    error
+	}{
+		{"Empty", nil, Zero, nil},
+		{"Quarters", map[int64]int64{25: 3}, New(3, 4), nil},
+		{"Mixed", map[int64]int64{1: 7, 5: 2, 10: 1, 25: 3, 100: 2, 500: 1}, New(802, 100), nil},
+		{"Large", map[int64]int64{M: 100}, New(M, 1), nil},
+		{"Cancels", map[int64]int64{M: 3, -M: 2, 1: -1}, New(M-1, 100), nil},
+		{"Overflow", map[int64]int64{M: 101}, Zero, rat128.ErrNumOverflow},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			x, err := rat128.FromDenominationCounts(c.Counts)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if x != c.X {
+				t.Errorf("got %v, want %v", x, c.X)
+			}
+		})
+	}
+}
+
+func TestN_ToByteChannel(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		X       rat128.N
+		Mode    rat128.RoundingMode
+		V       uint8
+		InRange bool
+	}{
+		{New(0, 1), rat128.RoundHalfAwayFromZero, 0, true},
+		{New(1, 1), rat128.RoundHalfAwayFromZero, 255, true},
+		{New(1, 2), rat128.RoundHalfAwayFromZero, 128, true},
+		{New(1, 2), rat128.RoundHalfToEven, 128, true},
+		{New(1, 2), rat128.RoundFloor, 127, true},
+		{New(3, 510), rat128.RoundHalfToEven, 2, true},
+		{New(5, 510), rat128.RoundHalfToEven, 2, true},
+		{New(1, 3), rat128.RoundHalfAwayFromZero, 85, true},
+		{New(M-1, M), rat128.RoundHalfAwayFromZero, 255, true},
+		{New(M-1, M), rat128.RoundFloor, 254, true},
+		{New(1, M), rat128.RoundCeiling, 1, true},
+		{New(3, 2), rat128.RoundHalfAwayFromZero, 255, false},
+		{New(-1, 2), rat128.RoundHalfAwayFromZero, 0, false},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s):%d", c.X.RationalString("_"), c.Mode), func(t *testing.T) {
+			v, ok := c.X.ToByteChannel(c.Mode)
+			if v != c.V || ok != c.InRange {
+				t.Errorf("got (%d, %t), want (%d, %t)", v, ok, c.V, c.InRange)
+			}
+		})
+	}
+}
+
+func TestFromByteChannel(t *testing.T) {
+	for v := 0; v < 256; v++ {
+		x := rat128.FromByteChannel(uint8(v))
+		if x != New(int64(v), 255) {
+			t.Errorf("%d: got %v, want %v", v, x, New(int64(v), 255))
+		}
+		for _, mode := range []rat128.RoundingMode{rat128.RoundHalfAwayFromZero, rat128.RoundFloor, rat128.RoundCeiling} {
+			if w, ok := x.ToByteChannel(mode); int(w) != v || !ok {
+				t.Errorf("%d: got (%d, %t) after round trip with mode %d", v, w, ok, mode)
+			}
+		}
+	}
+}
+
+func TestFromPPM(t *testing.T) {
+	cases := []struct {
+		PPM, X rat128.N
+	}{
+		{New(0, 1), New(0, 1)},
+		{New(1, 1), New(1, 1000000)},
+		{New(415, 1), New(83, 200000)},
+		{New(-250000, 1), New(-1, 4)},
+		{New(1, 2), New(1, 2000000)},
+		{New(1000000, 1), New(1, 1)},
+	}
+	for _, c := range cases {
+		t.Run(c.PPM.String(), func(t *testing.T) {
+			x := rat128.FromPPM(c.PPM)
+			if x != c.X {
+				t.Errorf("got %v, want %v", x, c.X)
+			}
+			if ppm, err := x.ToPPM(); err != nil || ppm != c.PPM {
+				t.Errorf("got (%v, %v) after round trip, want (%v, nil)", ppm, err, c.PPM)
+			}
+		})
+	}
+	if _, err := New(math.MaxInt64/1000, 1).ToPPM(); err != rat128.ErrNumOverflow {
+		t.Errorf("got error %v, want %v", err, rat128.ErrNumOverflow)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("FromPPM did not panic on overflow")
+		}
+	}()
+	rat128.FromPPM(New(1, math.MaxInt64))
+}
+
+func TestParseAspectRatioString(t *testing.T) {
+	cases := []struct {
+		S   string
+		X   rat128.N
+		Err error
+	}{
+		{"16:9", New(16, 9), nil},
+		{"1920:1080", New(16, 9), nil},
+		{"4:3", New(4, 3), nil},
+		{"1:1", New(1, 1), nil},
+		{"21:9", New(7, 3), nil},
+		{"9223372036854775807:1", New(math.MaxInt64, 1), nil},
+		{"16/9", Zero, rat128.ErrFmtInvalid},
+		{"16:9:1", Zero, rat128.ErrFmtInvalid},
+		{"0:9", Zero, rat128.ErrFmtInvalid},
+		{"16:0", Zero, rat128.ErrFmtInvalid},
+		{"-16:9", Zero, rat128.ErrFmtInvalid},
+		{"+16:9", Zero, rat128.ErrFmtInvalid},
+		{"1.5:1", Zero, rat128.ErrFmtInvalid},
+		{"16:", Zero, rat128.ErrFmtInvalid},
+		{" 16:9", Zero, rat128.ErrFmtInvalid},
+		{"9223372036854775808:1", Zero, rat128.ErrFmtInvalid},
+	}
+	for _, c := range cases {
+		t.Run(c.S, func(t *testing.T) {
+			x, err := rat128.ParseAspectRatioString(c.S)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if x != c.X {
+				t.Errorf("got %v, want %v", x, c.X)
+			}
+		})
+	}
+}
+
+func TestN_AspectRatioString(t *testing.T) {
+	for _, s := range []string{"16:9", "4:3", "1:1", "7:3", "1:2"} {
+		x, err := rat128.ParseAspectRatioString(s)
+		if err != nil {
+			t.Fatalf("%s: got unexpected error %v", s, err)
+		}
+		if got := x.AspectRatioString(); got != s {
+			t.Errorf("got %s, want %s", got, s)
+		}
+	}
+}
+
+func TestN_QuantizeToGrid(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		X        rat128.N
+		Division int64
+		Z        rat128.N
+		Index    int64
+		Err      error
+	}{
+		{New(0, 1), 4, New(0, 1), 0, nil},
+		{New(3, 4), 4, New(3, 4), 3, nil},
+		{New(7, 10), 4, New(3, 4), 3, nil},
+		{New(5, 8), 4, New(3, 4), 3, nil},
+		{New(-5, 8), 4, New(-3, 4), -3, nil},
+		{New(1, 3), 16, New(5, 16), 5, nil},
+		{New(17, 3), 3, New(17, 3), 17, nil},
+		{New(17, 3), 1, New(6, 1), 6, nil},
+		{New(1, 7), 12, New(1, 6), 2, nil},
+		{New(M, 1), 1, New(M, 1), M, nil},
+		{New(M, 1), 2, Zero, 0, rat128.ErrNumOverflow},
+		{New(1, 2), 0, Zero, 0, rat128.ErrDenInvalid},
+		{New(1, 2), -4, Zero, 0, rat128.ErrDenInvalid},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("(%s)@%d", c.X.RationalString("_"), c.Division), func(t *testing.T) {
+			z, err := c.X.QuantizeToGrid(c.Division)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if z != c.Z {
+				t.Errorf("got %v, want %v", z, c.Z)
+			}
+			if err != nil {
+				return
+			}
+			if k, ok := z.GridIndex(c.Division); k != c.Index || !ok {
+				t.Errorf("got index (%d, %t), want (%d, true)", k, ok, c.Index)
+			}
+		})
+	}
+}
+
+func TestN_GridIndex(t *testing.T) {
+	cases := []struct {
+		X        rat128.N
+		Division int64
+		Index    int64
+		OK       bool
+	}{
+		{New(3, 4), 4, 3, true},
+		{New(3, 4), 8, 6, true},
+		{New(-1, 2), 16, -8, true},
+		{New(2, 1), 3, 6, true},
+		{New(3, 4), 2, 0, false},
+		{New(1, 3), 4, 0, false},
+		{New(1, 2), 0, 0, false},
+		{New(math.MaxInt64, 1), 2, 0, false},
+	}
+	for _, c := range cases {
+		if k, ok := c.X.GridIndex(c.Division); k != c.Index || ok != c.OK {
+			t.Errorf("(%v)@%d: got (%d, %t), want (%d, %t)", c.X, c.Division, k, ok, c.Index, c.OK)
+		}
+	}
+}
+
+func TestRatioOfDurations(t *testing.T) {
+	cases := []struct {
+		A, B time.Duration
+		X    rat128.N
+		Err  error
+	}{
+		{500 * time.Millisecond, time.Second, New(1, 2), nil},
+		{time.Second, 500 * time.Millisecond, New(2, 1), nil},
+		{90 * time.Minute, time.Hour, New(3, 2), nil},
+		{-time.Second, 3 * time.Second, New(-1, 3), nil},
+		{time.Second, -3 * time.Second, New(-1, 3), nil},
+		{-time.Second, -3 * time.Second, New(1, 3), nil},
+		{time.Nanosecond, math.MaxInt64, New(1, math.MaxInt64), nil},
+		{0, time.Second, Zero, nil},
+		{math.MinInt64, -2, New(1<<62, 1), nil},
+		{math.MinInt64, math.MinInt64, New(1, 1), nil},
+		{-2, math.MinInt64, New(1, 1<<62), nil},
+		{math.MinInt64, 1, Zero, rat128.ErrNumOverflow},
+		{1, math.MinInt64, Zero, rat128.ErrDenOverflow},
+		{time.Second, 0, Zero, rat128.ErrDivByZero},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%v/%v", c.A, c.B), func(t *testing.T) {
+			x, err := rat128.RatioOfDurations(c.A, c.B)
+			if err != c.Err {
+				t.Errorf("got error %v, want %v", err, c.Err)
+			} else if x != c.X {
+				t.Errorf("got %v, want %v", x, c.X)
+			}
+		})
+	}
+}
+
+func TestFromFeetInches(t *testing.T) {
+	const M = math.MaxInt64
+	cases := []struct {
+		Feet, Inches, Sixteenths int64
+		X                        rat128.N
+		String                   string
+	}{
+		{0, 0, 0, Zero, `0' 0"`},
+		{5, 3, 8, New(127, 24), `5' 3 1/2"`},
+		{5, 0, 8, New(121, 24), `5' 0 1/2"`},
+		{6, 0, 0, New(6, 1), `6' 0"`},
+		{0, 11, 15, New(191, 192), `0' 11 15/16"`},
+		{0, 15, -8, New(29, 24), `1' 2 1/2"`},
+		{-5, -3, -8, New(-127, 24), `-5' 3 1/2"`},
+		{0, 0, -4, New(-1, 48), `-0' 0 1/4"`},
+		{M, 0, 0, New(M, 1), `9223372036854775807' 0"`},
+	}
+	for _, c := range cases {
+		t.Run(c.String, func(t *testing.T) {
+			x := rat128.FromFeetInches(c.Feet, c.Inches, c.Sixteenths)
+			if x != c.X {
+				t.Errorf("got %v, want %v", x, c.X)
+			}
+			if s, err := x.FeetInchesString(); err != nil || s != c.String {
+				t.Errorf("got (%q, %v), want (%q, nil)", s, err, c.String)
+			}
+		})
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("FromFeetInches did not panic on overflow")
+		}
+	}()
+	rat128.FromFeetInches(M, 12, 0)
+}
+
+func TestN_FeetInchesString(t *testing.T) {
+	for _, c := range []struct {
+		X   rat128.N
+		Err error
+	}{
+		{New(1, 3), nil},
+		{New(1, 384), rat128.ErrInexact},
+		{New(1, 7), rat128.ErrInexact},
+		{New(math.MaxInt64, 2), nil},
+	} {
+		if _, err := c.X.FeetInchesString(); err != c.Err {
+			t.Errorf("%v: got error %v, want %v", c.X, err, c.Err)
+		}
+	}
+}